@@ -0,0 +1,253 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AddressStyle determines how a formatted address is laid out
+type AddressStyle int
+
+const (
+	// AddressSingleLine joins the address lines with a comma, eg: for driver screens
+	AddressSingleLine AddressStyle = iota
+	// AddressMultiLine puts each address line on its own line, eg: for receipts
+	AddressMultiLine
+)
+
+// AddressParts holds the components of an address, any of which may be empty
+type AddressParts struct {
+	Premise     string // Flat, building name etc.
+	HouseNumber string
+	Street      string
+	Locality    string // Anything between the street and the city (district, neighbourhood, state...)
+	City        string
+	Postcode    string
+}
+
+type addressField int
+
+const (
+	premiseField addressField = iota
+	houseNumberField
+	streetField
+	localityField
+	cityField
+	postcodeField
+)
+
+func (p AddressParts) field(f addressField) string {
+	switch f {
+	case premiseField:
+		return p.Premise
+	case houseNumberField:
+		return p.HouseNumber
+	case streetField:
+		return p.Street
+	case localityField:
+		return p.Locality
+	case cityField:
+		return p.City
+	case postcodeField:
+		return p.Postcode
+	}
+	return ""
+}
+
+// addressLine is a set of fields rendered on one line, joined by sep (empty fields are skipped)
+type addressLine struct {
+	fields []addressField
+	sep    string
+}
+
+var (
+	premiseLine  = addressLine{[]addressField{premiseField}, ""}
+	localityLine = addressLine{[]addressField{localityField}, ""}
+
+	// defaultAddressFormat is used for any country we don't have specific conventions for
+	defaultAddressFormat = []addressLine{
+		premiseLine,
+		{[]addressField{houseNumberField, streetField}, " "},
+		localityLine,
+		{[]addressField{cityField, postcodeField}, " "},
+	}
+
+	// addressFormats holds the line ordering conventions, keyed by ISO 3166-1 country code
+	addressFormats = map[string][]addressLine{
+		"GB": {
+			premiseLine,
+			{[]addressField{houseNumberField, streetField}, " "},
+			localityLine,
+			{[]addressField{cityField}, ""},
+			{[]addressField{postcodeField}, ""},
+		},
+		"IE": {
+			premiseLine,
+			{[]addressField{houseNumberField, streetField}, " "},
+			localityLine,
+			{[]addressField{cityField}, ""},
+			{[]addressField{postcodeField}, ""},
+		},
+		"US": defaultAddressFormat,
+		"CA": defaultAddressFormat,
+		"FR": {
+			premiseLine,
+			{[]addressField{houseNumberField, streetField}, " "},
+			localityLine,
+			{[]addressField{postcodeField, cityField}, " "},
+		},
+		"ES": {
+			{[]addressField{streetField, houseNumberField}, ", "},
+			premiseLine,
+			localityLine,
+			{[]addressField{postcodeField, cityField}, " "},
+		},
+		"DE": {
+			premiseLine,
+			{[]addressField{streetField, houseNumberField}, " "},
+			localityLine,
+			{[]addressField{postcodeField, cityField}, " "},
+		},
+		"IT": {
+			premiseLine,
+			{[]addressField{streetField, houseNumberField}, " "},
+			localityLine,
+			{[]addressField{postcodeField, cityField}, " "},
+		},
+		"JP": {
+			{[]addressField{postcodeField}, ""},
+			{[]addressField{cityField}, ""},
+			localityLine,
+			{[]addressField{streetField, houseNumberField}, " "},
+			premiseLine,
+		},
+	}
+
+	// postcodePatterns recognise a postcode within a geocoded address component, keyed by ISO 3166-1 country code
+	postcodePatterns = map[string]*regexp.Regexp{
+		"GB": regexp.MustCompile(`(?i)\b[A-Z]{1,2}[0-9][A-Z0-9]? ?[0-9][A-Z]{2}\b`),
+		"IE": regexp.MustCompile(`(?i)\b[A-Z][0-9][0-9W] ?[A-Z0-9]{4}\b`),
+		"US": regexp.MustCompile(`\b[0-9]{5}(-[0-9]{4})?\b`),
+		"CA": regexp.MustCompile(`(?i)\b[A-Z][0-9][A-Z] ?[0-9][A-Z][0-9]\b`),
+		"FR": regexp.MustCompile(`\b[0-9]{5}\b`),
+		"ES": regexp.MustCompile(`\b[0-9]{5}\b`),
+		"DE": regexp.MustCompile(`\b[0-9]{5}\b`),
+		"IT": regexp.MustCompile(`\b[0-9]{5}\b`),
+		"JP": regexp.MustCompile(`\b[0-9]{3}-[0-9]{4}\b`),
+	}
+
+	leadingHouseNumberRe  = regexp.MustCompile(`^([0-9]+[A-Za-z]?(-[0-9]+[A-Za-z]?)?),?\s+(.+)$`)
+	trailingHouseNumberRe = regexp.MustCompile(`^(.+?),?\s+([0-9]+[A-Za-z]?(-[0-9]+[A-Za-z]?)?)$`)
+	houseNumberRe         = regexp.MustCompile(`^[0-9]+[A-Za-z]?(-[0-9]+[A-Za-z]?)?$`)
+)
+
+func addressFormat(country string) []addressLine {
+	if format, ok := addressFormats[strings.ToUpper(country)]; ok {
+		return format
+	}
+	return defaultAddressFormat
+}
+
+// numberFollowsStreet returns whether the country writes "Street 19" rather than "19 Street"
+func numberFollowsStreet(country string) bool {
+	for _, line := range addressFormat(country) {
+		for _, f := range line.fields {
+			switch f {
+			case streetField:
+				return true
+			case houseNumberField:
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// FormatAddress lays out the address parts following the conventions of the given country (an ISO 3166-1 code, as
+// found in Hob.Country). Empty parts are skipped, as are lines left empty.
+func FormatAddress(parts AddressParts, country string, style AddressStyle) string {
+	format := addressFormat(country)
+	lines := make([]string, 0, len(format))
+	for _, line := range format {
+		values := make([]string, 0, len(line.fields))
+		for _, f := range line.fields {
+			if v := strings.TrimSpace(parts.field(f)); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) > 0 {
+			lines = append(lines, strings.Join(values, line.sep))
+		}
+	}
+
+	if style == AddressMultiLine {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines, ", ")
+}
+
+// ParseAddress splits a free-text detail (usually a house number or flat) and a comma separated geocoded address
+// (eg: "Some Road, London SW1A 1AA") into its parts, using the postcode conventions of the given country
+func ParseAddress(detail, geocoded, country string) AddressParts {
+	parts := AddressParts{}
+
+	components := make([]string, 0)
+	for _, c := range strings.Split(geocoded, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			components = append(components, c)
+		}
+	}
+	if len(components) == 0 {
+		return parts
+	}
+
+	// The street comes first; it may already carry the house number, where the country puts it
+	parts.Street = components[0]
+	if numberFollowsStreet(country) {
+		if m := trailingHouseNumberRe.FindStringSubmatch(parts.Street); m != nil {
+			parts.Street, parts.HouseNumber = m[1], m[2]
+		}
+	} else if m := leadingHouseNumberRe.FindStringSubmatch(parts.Street); m != nil {
+		parts.HouseNumber, parts.Street = m[1], m[3]
+	}
+
+	// Some geocoders put the number in its own component ("Calle Mayor, 19, Madrid")
+	rest := components[1:]
+	if parts.HouseNumber == "" && len(rest) > 1 && houseNumberRe.MatchString(rest[0]) {
+		parts.HouseNumber = rest[0]
+		rest = rest[1:]
+	}
+
+	detail = strings.TrimSpace(detail)
+	switch {
+	case detail == "":
+	case parts.HouseNumber == "" && houseNumberRe.MatchString(detail):
+		parts.HouseNumber = detail
+	default:
+		parts.Premise = detail
+	}
+
+	// Pull the postcode out of whichever component contains it
+	if re, ok := postcodePatterns[strings.ToUpper(country)]; ok {
+		for i := len(rest) - 1; i >= 0; i-- {
+			if loc := re.FindStringIndex(rest[i]); loc != nil {
+				parts.Postcode = strings.ToUpper(rest[i][loc[0]:loc[1]])
+				rest[i] = strings.Join(strings.Fields(rest[i][:loc[0]]+" "+rest[i][loc[1]:]), " ")
+				break
+			}
+		}
+	}
+
+	remaining := make([]string, 0, len(rest))
+	for _, c := range rest {
+		if c != "" {
+			remaining = append(remaining, c)
+		}
+	}
+	if len(remaining) > 0 {
+		parts.City = remaining[len(remaining)-1]
+		parts.Locality = strings.Join(remaining[:len(remaining)-1], ", ")
+	}
+
+	return parts
+}
@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	testCases := []struct {
+		detail, geocoded, country string
+		expected                  AddressParts
+	}{
+		{"19", "Some Road, London", "GB", AddressParts{HouseNumber: "19", Street: "Some Road", City: "London"}},
+		{"19b", "Some Road, Islington, London N1 9GU", "GB", AddressParts{
+			HouseNumber: "19b", Street: "Some Road", Locality: "Islington", City: "London", Postcode: "N1 9GU",
+		}},
+		{"Flat 2", "19 Some Road, London, sw1a 1aa", "GB", AddressParts{
+			Premise: "Flat 2", HouseNumber: "19", Street: "Some Road", City: "London", Postcode: "SW1A 1AA",
+		}},
+		{"", "Calle Mayor, 19, 28013 Madrid", "ES", AddressParts{
+			HouseNumber: "19", Street: "Calle Mayor", City: "Madrid", Postcode: "28013",
+		}},
+		{"", "Hauptstraße 5, 10115 Berlin", "DE", AddressParts{
+			HouseNumber: "5", Street: "Hauptstraße", City: "Berlin", Postcode: "10115",
+		}},
+		{"350", "5th Avenue, New York, NY 10118", "US", AddressParts{
+			HouseNumber: "350", Street: "5th Avenue", Locality: "New York", City: "NY", Postcode: "10118",
+		}},
+		{"", "Route 66, Springfield", "US", AddressParts{Street: "Route 66", City: "Springfield"}},
+		{"19", "", "GB", AddressParts{}},
+	}
+
+	for _, tc := range testCases {
+		if actual := ParseAddress(tc.detail, tc.geocoded, tc.country); actual != tc.expected {
+			t.Errorf("Incorrect parse of '%s' '%s': expected %+v got %+v", tc.detail, tc.geocoded, tc.expected, actual)
+		}
+	}
+}
+
+func TestFormatAddress(t *testing.T) {
+	testCases := []struct {
+		parts      AddressParts
+		country    string
+		singleLine string
+		multiLine  string
+	}{
+		{
+			AddressParts{HouseNumber: "19", Street: "Some Road", City: "London", Postcode: "N1 9GU"},
+			"GB",
+			"19 Some Road, London, N1 9GU",
+			"19 Some Road\nLondon\nN1 9GU",
+		},
+		{
+			AddressParts{HouseNumber: "19", Street: "Calle Mayor", City: "Madrid", Postcode: "28013", Premise: "3º B"},
+			"ES",
+			"Calle Mayor, 19, 3º B, 28013 Madrid",
+			"Calle Mayor, 19\n3º B\n28013 Madrid",
+		},
+		{
+			AddressParts{HouseNumber: "5", Street: "Hauptstraße", City: "Berlin", Postcode: "10115"},
+			"de",
+			"Hauptstraße 5, 10115 Berlin",
+			"Hauptstraße 5\n10115 Berlin",
+		},
+		{
+			AddressParts{HouseNumber: "350", Street: "5th Avenue", Locality: "New York", City: "NY", Postcode: "10118"},
+			"US",
+			"350 5th Avenue, New York, NY 10118",
+			"350 5th Avenue\nNew York\nNY 10118",
+		},
+		{
+			AddressParts{HouseNumber: "1-2", Street: "Marunouchi", City: "Tokyo", Postcode: "100-0005"},
+			"JP",
+			"100-0005, Tokyo, Marunouchi 1-2",
+			"100-0005\nTokyo\nMarunouchi 1-2",
+		},
+		{
+			AddressParts{Street: "Some Road", City: "Somewhere"},
+			"XX",
+			"Some Road, Somewhere",
+			"Some Road\nSomewhere",
+		},
+		{AddressParts{}, "GB", "", ""},
+	}
+
+	for _, tc := range testCases {
+		if actual := FormatAddress(tc.parts, tc.country, AddressSingleLine); actual != tc.singleLine {
+			t.Errorf("Incorrect single line address for %s: expected '%s' got '%s'", tc.country, tc.singleLine, actual)
+		}
+		if actual := FormatAddress(tc.parts, tc.country, AddressMultiLine); actual != tc.multiLine {
+			t.Errorf("Incorrect multi line address for %s: expected '%s' got '%s'", tc.country, tc.multiLine, actual)
+		}
+	}
+}
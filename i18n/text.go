@@ -0,0 +1,110 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+
+	localisation "github.com/HailoOSS/go-hailo-lib/localisation/hob"
+)
+
+// I18nTexts is a set of translations of one piece of configured text (eg: priority peak texts), exactly one of
+// which should be flagged as the default language
+type I18nTexts []localisation.I18nText
+
+// Resolve returns the text most appropriate for the given locale (eg: "es_ES", "es-ES" or "es"). An exact match is
+// preferred, then a text for the bare language, then a text for the same language in another region, and finally
+// the default text. An empty string is returned if none of these exist.
+func (texts I18nTexts) Resolve(locale string) string {
+	if text, ok := texts.find(locale); ok {
+		return text.Text
+	}
+	if text, ok := texts.Default(); ok {
+		return text.Text
+	}
+	return ""
+}
+
+// Default returns the text flagged as being in the default language, if there is one
+func (texts I18nTexts) Default() (localisation.I18nText, bool) {
+	for _, text := range texts {
+		if text.IsDefaultLang {
+			return text, true
+		}
+	}
+	return localisation.I18nText{}, false
+}
+
+// Validate checks that exactly one default text exists and that no language is defined more than once
+func (texts I18nTexts) Validate() error {
+	defaults := 0
+	seen := make(map[string]bool, len(texts))
+	for _, text := range texts {
+		if text.IsDefaultLang {
+			defaults++
+		}
+		lang := NormaliseLocale(text.Language)
+		if lang == "" {
+			return fmt.Errorf("Missing language for text '%s'", text.Id)
+		}
+		if seen[lang] {
+			return fmt.Errorf("Duplicate text for language '%s'", text.Language)
+		}
+		seen[lang] = true
+	}
+
+	switch defaults {
+	case 0:
+		return fmt.Errorf("No default language text defined")
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf("Expected exactly one default language text, got %d", defaults)
+	}
+}
+
+func (texts I18nTexts) find(locale string) (localisation.I18nText, bool) {
+	locale = NormaliseLocale(locale)
+	if locale == "" {
+		return localisation.I18nText{}, false
+	}
+	lang := Language(locale)
+
+	var langMatch, regionMatch *localisation.I18nText
+	for i := range texts {
+		textLocale := NormaliseLocale(texts[i].Language)
+		switch {
+		case textLocale == locale:
+			return texts[i], true
+		case textLocale == lang && langMatch == nil:
+			langMatch = &texts[i]
+		case Language(textLocale) == lang && regionMatch == nil:
+			regionMatch = &texts[i]
+		}
+	}
+
+	if langMatch != nil {
+		return *langMatch, true
+	}
+	if regionMatch != nil {
+		return *regionMatch, true
+	}
+	return localisation.I18nText{}, false
+}
+
+// NormaliseLocale turns "en-gb", "EN_GB" etc. into "en_GB"
+func NormaliseLocale(locale string) string {
+	locale = strings.Replace(strings.TrimSpace(locale), "-", "_", -1)
+	parts := strings.SplitN(locale, "_", 2)
+	if len(parts) == 1 {
+		return strings.ToLower(parts[0])
+	}
+	return strings.ToLower(parts[0]) + "_" + strings.ToUpper(parts[1])
+}
+
+// Language returns the language part of a normalised locale, eg: "en" for "en_GB"
+func Language(locale string) string {
+	if i := strings.Index(locale, "_"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
@@ -0,0 +1,91 @@
+package i18n
+
+import (
+	"testing"
+
+	localisation "github.com/HailoOSS/go-hailo-lib/localisation/hob"
+)
+
+var testTexts = I18nTexts{
+	{Id: "1", Language: "en_GB", Text: "Peak time", IsDefaultLang: true},
+	{Id: "2", Language: "es", Text: "Hora punta"},
+	{Id: "3", Language: "es_MX", Text: "Hora pico"},
+	{Id: "4", Language: "fr-FR", Text: "Heure de pointe"},
+}
+
+func TestI18nTextsResolve(t *testing.T) {
+	testCases := []struct {
+		texts    I18nTexts
+		locale   string
+		expected string
+	}{
+		// exact matches, in any notation
+		{testTexts, "en_GB", "Peak time"},
+		{testTexts, "es_MX", "Hora pico"},
+		{testTexts, "es-mx", "Hora pico"},
+		{testTexts, "fr_FR", "Heure de pointe"},
+
+		// bare language preferred over another region
+		{testTexts, "es_ES", "Hora punta"},
+		{testTexts, "es", "Hora punta"},
+
+		// other region of the same language
+		{testTexts, "fr_CA", "Heure de pointe"},
+		{testTexts, "en_US", "Peak time"},
+
+		// default
+		{testTexts, "ja_JP", "Peak time"},
+		{testTexts, "", "Peak time"},
+
+		// nothing to fall back on
+		{I18nTexts{{Language: "es", Text: "Hora punta"}}, "ja_JP", ""},
+		{nil, "en_GB", ""},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.texts.Resolve(tc.locale); actual != tc.expected {
+			t.Errorf("Incorrect text for locale '%s': expected '%s' got '%s'", tc.locale, tc.expected, actual)
+		}
+	}
+}
+
+func TestI18nTextsValidate(t *testing.T) {
+	testCases := []struct {
+		texts I18nTexts
+		valid bool
+	}{
+		{testTexts, true},
+		{nil, false},
+		{I18nTexts{{Language: "en", Text: "Peak time"}}, false},
+		{I18nTexts{
+			{Language: "en", Text: "Peak time", IsDefaultLang: true},
+			{Language: "es", Text: "Hora punta", IsDefaultLang: true},
+		}, false},
+		{I18nTexts{
+			{Language: "en_GB", Text: "Peak time", IsDefaultLang: true},
+			{Language: "en-gb", Text: "Rush hour"},
+		}, false},
+		{I18nTexts{{Language: "", Text: "Peak time", IsDefaultLang: true}}, false},
+	}
+
+	for i, tc := range testCases {
+		err := tc.texts.Validate()
+		if tc.valid && err != nil {
+			t.Errorf("Expected texts (%d) to be valid, got: %v", i, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("Expected texts (%d) to be invalid", i)
+		}
+	}
+}
+
+func TestI18nTextsDefault(t *testing.T) {
+	text, ok := testTexts.Default()
+	if !ok || text.Id != "1" {
+		t.Errorf("Incorrect default text: %+v", text)
+	}
+
+	if _, ok := (I18nTexts{localisation.I18nText{Language: "es"}}).Default(); ok {
+		t.Errorf("Expected no default text")
+	}
+}
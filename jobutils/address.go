@@ -1,6 +1,12 @@
 package jobutils
 
-import jobproto "github.com/HailoOSS/job-service/proto"
+import (
+	"fmt"
+
+	"github.com/HailoOSS/go-hailo-lib/i18n"
+	localisation "github.com/HailoOSS/go-hailo-lib/localisation/hob"
+	jobproto "github.com/HailoOSS/job-service/proto"
+)
 
 // Produces an address string (including the detail prepended) HOB is
 // included in case there needs to be some localisation in future.
@@ -19,3 +25,17 @@ func BuildAddressString(addr *jobproto.Address, hob string) string {
 
 	return detail + " " + geocoded
 }
+
+// FormatAddress produces an address string laid out following the conventions
+// of the HOB's country (eg: "Calle Mayor, 19" in Spain vs "19 Some Road" in
+// the UK), either on a single line or over multiple lines.
+func FormatAddress(addr *jobproto.Address, hobCode string, style i18n.AddressStyle) (string, error) {
+	hob, err := localisation.GetHob(hobCode)
+	if err != nil {
+		return "", fmt.Errorf("Invalid HOB: %s err:%v", hobCode, err)
+	}
+
+	country := hob.Country.ISO_3166_1
+	parts := i18n.ParseAddress(addr.GetDetail(), addr.GetGeocoded(), country)
+	return i18n.FormatAddress(parts, country, style), nil
+}
@@ -3,6 +3,8 @@ package jobutils
 import (
 	"testing"
 
+	"github.com/HailoOSS/go-hailo-lib/i18n"
+	localisation "github.com/HailoOSS/go-hailo-lib/localisation/hob"
 	jobproto "github.com/HailoOSS/job-service/proto"
 	"github.com/HailoOSS/protobuf/proto"
 )
@@ -44,3 +46,70 @@ func TestBuildAddressString(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatAddress(t *testing.T) {
+	mockCache := &localisation.MockHobsCache{}
+	mockCache.On("ReadHob", "LON").Return(&localisation.Hob{
+		Code:    "LON",
+		Country: localisation.Country{ISO_3166_1: "GB"},
+	})
+	mockCache.On("ReadHob", "MAD").Return(&localisation.Hob{
+		Code:    "MAD",
+		Country: localisation.Country{ISO_3166_1: "ES"},
+	})
+	localisation.Cache = mockCache
+
+	testCases := []struct {
+		Address *jobproto.Address
+		Hob     string
+		Style   i18n.AddressStyle
+		Output  string
+	}{
+		{
+			Address: &jobproto.Address{
+				Geocoded: proto.String("Some Road, London N1 9GU"),
+				Detail:   proto.String("19"),
+			},
+			Hob:    "LON",
+			Style:  i18n.AddressSingleLine,
+			Output: "19 Some Road, London, N1 9GU",
+		},
+		{
+			Address: &jobproto.Address{
+				Geocoded: proto.String("Some Road, London N1 9GU"),
+				Detail:   proto.String("19"),
+			},
+			Hob:    "LON",
+			Style:  i18n.AddressMultiLine,
+			Output: "19 Some Road\nLondon\nN1 9GU",
+		},
+		{
+			Address: &jobproto.Address{
+				Geocoded: proto.String("Calle Mayor, 28013 Madrid"),
+				Detail:   proto.String("19"),
+			},
+			Hob:    "MAD",
+			Style:  i18n.AddressSingleLine,
+			Output: "Calle Mayor, 19, 28013 Madrid",
+		},
+		{
+			Address: &jobproto.Address{
+				Detail: proto.String("19b"),
+			},
+			Hob:    "MAD",
+			Style:  i18n.AddressSingleLine,
+			Output: "",
+		},
+	}
+
+	for _, test := range testCases {
+		res, err := FormatAddress(test.Address, test.Hob, test.Style)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if res != test.Output {
+			t.Errorf("Result '%s' does not match expected '%s'", res, test.Output)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package jobutils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/HailoOSS/go-hailo-lib/geo"
+	jobproto "github.com/HailoOSS/job-service/proto"
+)
+
+const (
+	// Addresses scoring at or above this are considered to be the same place
+	SameAddressThreshold = 0.8
+
+	// Coordinates further apart than this (in meters) score nothing for location
+	maxMatchDistance = 250.0
+
+	// How much the location counts towards the similarity when both addresses have coordinates
+	geoWeight = 0.5
+)
+
+var (
+	// Abbreviations expanded wherever they appear in an address
+	addressAbbreviations = map[string]string{
+		"rd":   "road",
+		"ave":  "avenue",
+		"av":   "avenue",
+		"ln":   "lane",
+		"dr":   "drive",
+		"sq":   "square",
+		"pl":   "place",
+		"ct":   "court",
+		"cres": "crescent",
+		"gdns": "gardens",
+		"blvd": "boulevard",
+		"hwy":  "highway",
+		"pkwy": "parkway",
+		"tce":  "terrace",
+		"ter":  "terrace",
+		"bldg": "building",
+		"apt":  "apartment",
+		"avda": "avenida",
+		"nth":  "north",
+		"sth":  "south",
+		"flt":  "flat",
+		"mt":   "mount",
+		"hse":  "house",
+		"pza":  "plaza",
+	}
+
+	ukOutwardCodeRe = regexp.MustCompile(`^[a-z]{1,2}[0-9][a-z0-9]?$`)
+	ukInwardCodeRe  = regexp.MustCompile(`^[0-9][a-z]{2}$`)
+	ukPostcodeRe    = regexp.MustCompile(`^([a-z]{1,2}[0-9][a-z0-9]?)([0-9][a-z]{2})$`)
+)
+
+// NormaliseAddress produces a canonical form of an address (detail and geocoded) suitable for comparison:
+// lower case, punctuation removed, common abbreviations expanded and postcodes consistently spaced
+func NormaliseAddress(addr *jobproto.Address) string {
+	components := make([]string, 0)
+	for _, c := range strings.Split(addr.GetDetail()+" "+addr.GetGeocoded(), ",") {
+		if c = normaliseComponent(c); c != "" {
+			components = append(components, c)
+		}
+	}
+	return strings.Join(components, " ")
+}
+
+func normaliseComponent(component string) string {
+	words := strings.FieldsFunc(strings.ToLower(component), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	result := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+
+		// "St" ending a name is a street, elsewhere it's a saint (19 St Pancras Way)
+		if word == "st" {
+			if i == len(words)-1 {
+				word = "street"
+			} else {
+				word = "saint"
+			}
+		} else if expanded, ok := addressAbbreviations[word]; ok {
+			word = expanded
+		}
+
+		// Postcodes: "SW1A1AA" and "SW1A 1AA" both become "sw1a 1aa"
+		if m := ukPostcodeRe.FindStringSubmatch(word); m != nil {
+			word = m[1] + " " + m[2]
+		} else if ukOutwardCodeRe.MatchString(word) && i+1 < len(words) && ukInwardCodeRe.MatchString(words[i+1]) {
+			word = word + " " + words[i+1]
+			i++
+		}
+
+		result = append(result, word)
+	}
+
+	return strings.Join(result, " ")
+}
+
+// AddressSimilarity scores how likely two addresses refer to the same place, between 0 (nothing in common) and 1
+// (identical). The text of the addresses is compared after normalisation; if both addresses have coordinates, the
+// distance between them is taken into account too.
+func AddressSimilarity(a, b *jobproto.Address) float64 {
+	textScore := textSimilarity(NormaliseAddress(a), NormaliseAddress(b))
+
+	if !hasCoordinates(a) || !hasCoordinates(b) {
+		return textScore
+	}
+
+	distance := geo.HaversineInMeters(a.GetLatitude(), a.GetLongitude(), b.GetLatitude(), b.GetLongitude())
+	geoScore := 0.0
+	if distance < maxMatchDistance {
+		geoScore = 1 - distance/maxMatchDistance
+	}
+
+	return (1-geoWeight)*textScore + geoWeight*geoScore
+}
+
+// SameAddress returns whether two addresses are similar enough to be considered the same place
+func SameAddress(a, b *jobproto.Address) bool {
+	return AddressSimilarity(a, b) >= SameAddressThreshold
+}
+
+func hasCoordinates(addr *jobproto.Address) bool {
+	return addr != nil && addr.Latitude != nil && addr.Longitude != nil
+}
+
+// textSimilarity averages the overlap of the words in each string (which is insensitive to word order) with their
+// edit distance (which is tolerant of typos)
+func textSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+
+	return (wordOverlap(a, b) + editSimilarity(a, b)) / 2
+}
+
+// wordOverlap is the Sørensen–Dice coefficient of the words in each string
+func wordOverlap(a, b string) float64 {
+	aWords := strings.Fields(a)
+	bWords := strings.Fields(b)
+
+	counts := make(map[string]int, len(aWords))
+	for _, w := range aWords {
+		counts[w]++
+	}
+	common := 0
+	for _, w := range bWords {
+		if counts[w] > 0 {
+			counts[w]--
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(aWords)+len(bWords))
+}
+
+// editSimilarity is 1 minus the Levenshtein distance between the strings, relative to the longest of them
+func editSimilarity(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	longest := len(ar)
+	if len(br) > longest {
+		longest = len(br)
+	}
+	return 1 - float64(prev[len(br)])/float64(longest)
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
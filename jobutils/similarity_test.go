@@ -0,0 +1,107 @@
+package jobutils
+
+import (
+	"testing"
+
+	jobproto "github.com/HailoOSS/job-service/proto"
+	"github.com/HailoOSS/protobuf/proto"
+)
+
+func TestNormaliseAddress(t *testing.T) {
+	testCases := []struct {
+		Address *jobproto.Address
+		Output  string
+	}{
+		{
+			Address: &jobproto.Address{
+				Geocoded: proto.String("Some St., London SW1A1AA"),
+				Detail:   proto.String("19"),
+			},
+			Output: "19 some street london sw1a 1aa",
+		},
+		{
+			Address: &jobproto.Address{
+				Geocoded: proto.String("19 SOME STREET, LONDON, sw1a 1aa"),
+			},
+			Output: "19 some street london sw1a 1aa",
+		},
+		{
+			Address: &jobproto.Address{
+				Geocoded: proto.String("St Pancras Rd, London NW1"),
+				Detail:   proto.String("Flat 2"),
+			},
+			Output: "flat 2 saint pancras road london nw1",
+		},
+		{
+			Address: &jobproto.Address{},
+			Output:  "",
+		},
+	}
+
+	for _, test := range testCases {
+		if res := NormaliseAddress(test.Address); res != test.Output {
+			t.Errorf("Result '%s' does not match expected '%s'", res, test.Output)
+		}
+	}
+}
+
+func TestAddressSimilarity(t *testing.T) {
+	home := &jobproto.Address{
+		Geocoded:  proto.String("Some Street, London SW1A 1AA"),
+		Detail:    proto.String("19"),
+		Latitude:  proto.Float64(51.501),
+		Longitude: proto.Float64(-0.1416),
+	}
+
+	testCases := []struct {
+		Address *jobproto.Address
+		Same    bool
+	}{
+		// Same text, abbreviated and without coordinates
+		{
+			Address: &jobproto.Address{Geocoded: proto.String("19 Some St, London SW1A1AA")},
+			Same:    true,
+		},
+		// Typo, but right next door
+		{
+			Address: &jobproto.Address{
+				Geocoded:  proto.String("19 Somme Street, London SW1A 1AA"),
+				Latitude:  proto.Float64(51.5011),
+				Longitude: proto.Float64(-0.1417),
+			},
+			Same: true,
+		},
+		// Same text but miles away
+		{
+			Address: &jobproto.Address{
+				Geocoded:  proto.String("19 Some Street, London SW1A 1AA"),
+				Latitude:  proto.Float64(51.6),
+				Longitude: proto.Float64(-0.2),
+			},
+			Same: false,
+		},
+		// Somewhere else entirely
+		{
+			Address: &jobproto.Address{Geocoded: proto.String("Calle Mayor, 19, 28013 Madrid")},
+			Same:    false,
+		},
+		{
+			Address: &jobproto.Address{},
+			Same:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		score := AddressSimilarity(home, test.Address)
+		if score < 0 || score > 1 {
+			t.Errorf("Similarity %v out of range for '%s'", score, test.Address.GetGeocoded())
+		}
+		if SameAddress(home, test.Address) != test.Same {
+			t.Errorf("Expected same=%v for '%s', got score %v", test.Same, test.Address.GetGeocoded(), score)
+		}
+	}
+
+	if score := AddressSimilarity(home, home); score != 1 {
+		t.Errorf("Expected identical addresses to score 1, got %v", score)
+	}
+}
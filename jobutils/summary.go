@@ -0,0 +1,124 @@
+package jobutils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HailoOSS/go-hailo-lib/geo"
+	localisation "github.com/HailoOSS/go-hailo-lib/localisation/hob"
+	"github.com/HailoOSS/go-hailo-lib/localisation/money"
+	"github.com/HailoOSS/go-hailo-lib/multierror"
+	jobproto "github.com/HailoOSS/job-service/proto"
+)
+
+// Job is the part of a job proto needed to summarise it. Timestamps are unix
+// seconds, zero if the job never reached that state; the fare is in the
+// HOB's currency units, as are the fare limits on the service type.
+type Job interface {
+	GetPickup() *jobproto.Address
+	GetDestination() *jobproto.Address
+	GetArrivedTimestamp() int64
+	GetPobTimestamp() int64
+	GetCompletedTimestamp() int64
+	GetFare() float64
+}
+
+// The job proto is what's usually summarised
+var _ Job = (*jobproto.Job)(nil)
+
+// JobSummary holds the metrics every receipt, stat and driver earning is
+// derived from
+type JobSummary struct {
+	Distance              float64       // Straight line (Haversine) distance from pickup to destination (km), not the distance travelled; 0 if unknown
+	Duration              time.Duration // From passenger on board to completion
+	WaitingTime           time.Duration // From driver arrival to passenger on board
+	ChargeableWait        time.Duration // Waiting time beyond the service type's free waiting time
+	Fare                  float64
+	FareBelowMinimum      bool // Below the lowest fare the service type accepts
+	FareAboveMaximum      bool // Above the highest fare the service type accepts
+	FareNeedsVerification bool // Acceptable, but unusually low or high so should be confirmed
+}
+
+// SummaryError describes a problem found with a job while summarising it
+type SummaryError struct {
+	Field  string
+	Reason string
+}
+
+func (e *SummaryError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// SummariseJob computes the job metrics for a job of the given service type,
+// whose fare is in the given currency. The summary is always returned, with
+// whatever could be derived; anything inconsistent about the job (missing or
+// out of order timestamps, a fare outside the service type's limits as
+// checked by money.CheckFare) is reported in the errors. Fares which only
+// need verification are flagged in the summary, but aren't errors.
+func SummariseJob(job Job, serviceType *localisation.ServiceType, currency string) (*JobSummary, *multierror.MultiError) {
+	summary := &JobSummary{
+		Fare: job.GetFare(),
+	}
+	errs := multierror.New()
+
+	pickup, destination := job.GetPickup(), job.GetDestination()
+	if hasCoordinates(pickup) && hasCoordinates(destination) {
+		summary.Distance = geo.Haversine(pickup.GetLatitude(), pickup.GetLongitude(),
+			destination.GetLatitude(), destination.GetLongitude())
+	}
+
+	arrived := job.GetArrivedTimestamp()
+	pob := job.GetPobTimestamp()
+	completed := job.GetCompletedTimestamp()
+
+	switch {
+	case pob == 0:
+		errs.Add(&SummaryError{"pobTimestamp", "passenger was never on board"})
+	case completed == 0:
+		errs.Add(&SummaryError{"completedTimestamp", "job was never completed"})
+	case completed < pob:
+		errs.Add(&SummaryError{"completedTimestamp", "job completed before passenger on board"})
+	default:
+		summary.Duration = time.Duration(completed-pob) * time.Second
+	}
+
+	if arrived != 0 && pob != 0 {
+		if pob < arrived {
+			errs.Add(&SummaryError{"pobTimestamp", "passenger on board before driver arrived"})
+		} else {
+			summary.WaitingTime = time.Duration(pob-arrived) * time.Second
+		}
+	}
+
+	if serviceType == nil {
+		errs.Add(&SummaryError{"serviceType", "no service type to check against"})
+		return summary, errs
+	}
+
+	if free := serviceType.FreeWaitingTime.Duration(); summary.WaitingTime > free {
+		summary.ChargeableWait = summary.WaitingTime - free
+	}
+
+	fare, err := money.FromMajor(summary.Fare, currency, money.RoundHalfUp)
+	if err != nil {
+		errs.Add(&SummaryError{"fare", err.Error()})
+		return summary, errs
+	}
+	check, err := money.CheckFare(fare, serviceType)
+	if err != nil {
+		errs.Add(&SummaryError{"serviceType", err.Error()})
+		return summary, errs
+	}
+	switch check.Verdict {
+	case money.FareBelowMinimum:
+		summary.FareBelowMinimum = true
+		errs.Add(&SummaryError{"fare", fmt.Sprintf("%v is below the minimum fare %v", check.Fare, check.Limit)})
+	case money.FareAboveMaximum:
+		summary.FareAboveMaximum = true
+		errs.Add(&SummaryError{"fare", fmt.Sprintf("%v is above the maximum fare %v", check.Fare, check.Limit)})
+	case money.FareNeedsVerification:
+		summary.FareNeedsVerification = true
+	}
+
+	return summary, errs
+}
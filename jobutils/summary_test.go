@@ -0,0 +1,142 @@
+package jobutils
+
+import (
+	"testing"
+	"time"
+
+	localisation "github.com/HailoOSS/go-hailo-lib/localisation/hob"
+	jobproto "github.com/HailoOSS/job-service/proto"
+	"github.com/HailoOSS/protobuf/proto"
+)
+
+type testJob struct {
+	pickup, destination     *jobproto.Address
+	arrived, pob, completed int64
+	fare                    float64
+}
+
+func (j *testJob) GetPickup() *jobproto.Address      { return j.pickup }
+func (j *testJob) GetDestination() *jobproto.Address { return j.destination }
+func (j *testJob) GetArrivedTimestamp() int64        { return j.arrived }
+func (j *testJob) GetPobTimestamp() int64            { return j.pob }
+func (j *testJob) GetCompletedTimestamp() int64      { return j.completed }
+func (j *testJob) GetFare() float64                  { return j.fare }
+
+func TestSummariseJob(t *testing.T) {
+	serviceType := &localisation.ServiceType{
+		FreeWaitingTime: localisation.JsonDuration("2m0s"),
+		MinFare:         2.50,
+		MaxFare:         999.00,
+	}
+	pickup := &jobproto.Address{Latitude: proto.Float64(51.5), Longitude: proto.Float64(-0.12)}
+	destination := &jobproto.Address{Latitude: proto.Float64(51.6), Longitude: proto.Float64(-0.12)}
+
+	testCases := []struct {
+		job      *testJob
+		expected JobSummary
+		errors   int
+	}{
+		{
+			job: &testJob{pickup, destination, 1000, 1300, 2500, 25.60},
+			expected: JobSummary{
+				Duration:       20 * time.Minute,
+				WaitingTime:    5 * time.Minute,
+				ChargeableWait: 3 * time.Minute,
+				Fare:           25.60,
+			},
+		},
+		{
+			job: &testJob{nil, nil, 1000, 1060, 1200, 1.00},
+			expected: JobSummary{
+				Duration:         140 * time.Second,
+				WaitingTime:      time.Minute,
+				Fare:             1.00,
+				FareBelowMinimum: true,
+			},
+			errors: 1,
+		},
+		{
+			job: &testJob{pickup, nil, 0, 1000, 0, 1000.00},
+			expected: JobSummary{
+				Fare:             1000.00,
+				FareAboveMaximum: true,
+			},
+			errors: 2,
+		},
+		{
+			job: &testJob{pickup, destination, 1300, 1000, 900, 10.00},
+			expected: JobSummary{
+				Fare: 10.00,
+			},
+			errors: 2,
+		},
+	}
+
+	for i, tc := range testCases {
+		summary, errs := SummariseJob(tc.job, serviceType, "GBP")
+
+		// Distance is checked separately as it's a float
+		distance := summary.Distance
+		summary.Distance = 0
+		if *summary != tc.expected {
+			t.Errorf("Incorrect summary (%d): expected %+v got %+v", i, tc.expected, *summary)
+		}
+		if errs.Count() != tc.errors {
+			t.Errorf("Incorrect number of errors (%d): expected %d got %d: %v", i, tc.errors, errs.Count(), errs.Errors())
+		}
+		if hasCoordinates(tc.job.pickup) && hasCoordinates(tc.job.destination) && (distance < 11 || distance > 11.2) {
+			t.Errorf("Incorrect distance (%d): %v", i, distance)
+		}
+	}
+}
+
+func TestSummariseJobWithoutServiceType(t *testing.T) {
+	summary, errs := SummariseJob(&testJob{nil, nil, 1000, 1300, 2500, 25.60}, nil, "GBP")
+	if summary.Duration != 20*time.Minute {
+		t.Errorf("Expected duration to be computed without a service type, got %v", summary.Duration)
+	}
+	if !errs.AnyErrors() {
+		t.Errorf("Expected an error for the missing service type")
+	}
+}
+
+func TestSummariseJobFareNeedsVerification(t *testing.T) {
+	// The fare limits are checked as money.CheckFare does, so a fare below MinFare but above MinAcceptableFare (or
+	// above MaxUnverifiedFare) needs verification, rather than being below the minimum
+	serviceType := &localisation.ServiceType{MinFare: 2.50, MinAcceptableFare: 2.00, MaxUnverifiedFare: 100.00}
+	summary, errs := SummariseJob(&testJob{nil, nil, 1000, 1000, 1200, 2.20}, serviceType, "GBP")
+	if !summary.FareNeedsVerification || summary.FareBelowMinimum || errs.AnyErrors() {
+		t.Errorf("Expected a fare above MinAcceptableFare to need verification: %+v %v", summary, errs.Errors())
+	}
+
+	summary, errs = SummariseJob(&testJob{nil, nil, 1000, 1000, 1200, 150.00}, serviceType, "GBP")
+	if !summary.FareNeedsVerification || summary.FareAboveMaximum || errs.AnyErrors() {
+		t.Errorf("Expected a fare above MaxUnverifiedFare to need verification: %+v %v", summary, errs.Errors())
+	}
+
+	summary, errs = SummariseJob(&testJob{nil, nil, 1000, 1000, 1200, 5.00}, serviceType, "GBP")
+	if summary.FareNeedsVerification || errs.AnyErrors() {
+		t.Errorf("Expected a fare within the limits to be OK: %+v %v", summary, errs.Errors())
+	}
+
+	summary, errs = SummariseJob(&testJob{nil, nil, 1000, 1000, 1200, 1.99}, serviceType, "GBP")
+	if !summary.FareBelowMinimum || errs.Count() != 1 {
+		t.Errorf("Expected a fare below MinAcceptableFare to be below the minimum: %v", errs.Errors())
+	}
+}
+
+func TestSummariseJobProto(t *testing.T) {
+	job := &jobproto.Job{
+		ArrivedTimestamp:   proto.Int64(1000),
+		PobTimestamp:       proto.Int64(1300),
+		CompletedTimestamp: proto.Int64(2500),
+		Fare:               proto.Float64(25.60),
+	}
+	summary, errs := SummariseJob(job, &localisation.ServiceType{}, "GBP")
+	if errs.AnyErrors() {
+		t.Errorf("Unexpected errors: %v", errs.Errors())
+	}
+	if summary.Duration != 20*time.Minute || summary.WaitingTime != 5*time.Minute || summary.Fare != 25.60 {
+		t.Errorf("Incorrect summary of a job proto: %+v", summary)
+	}
+}
@@ -0,0 +1,203 @@
+package localisation
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// AuthDecision records a single decision made by one of the HOB authorisers
+type AuthDecision struct {
+	Time          time.Time
+	MessageID     string
+	Service       string
+	Endpoint      string
+	Roles         []string     // The roles required by the authoriser
+	RolesTried    []string     // Every role checked, including those scoped to a HOB
+	Discriminated bool         // Whether the HOB discriminator was consulted (it isn't if a global role matched)
+	CacheHit      bool         // Whether the discriminated HOB(s) came from the cache
+	Hobs          []string     // The HOB(s) discriminated, if any
+	DeniedHobs    []string     // The HOB(s) the caller lacked the roles in
+	Authorised    bool         // The outcome
+	Err           errors.Error // The error returned to the caller if not authorised
+}
+
+// AuditSink receives every decision made by the HOB authorisers. Implementations must be safe for concurrent use.
+type AuditSink interface {
+	Record(decision *AuthDecision)
+}
+
+// AuditSinkFunc adapts a function to an AuditSink
+type AuditSinkFunc func(decision *AuthDecision)
+
+func (f AuditSinkFunc) Record(decision *AuthDecision) {
+	f(decision)
+}
+
+var (
+	// DefaultAuditCounter counts every decision made by the HOB authorisers and logs them
+	DefaultAuditCounter = NewCountingAuditSink(LogAuditSink{})
+
+	auditSink     AuditSink = DefaultAuditCounter
+	auditSinkLock sync.RWMutex
+)
+
+// SetAuditSink replaces the sink receiving authorisation decisions (the default being DefaultAuditCounter). Pass nil to
+// stop recording them.
+func SetAuditSink(sink AuditSink) {
+	auditSinkLock.Lock()
+	defer auditSinkLock.Unlock()
+	auditSink = sink
+}
+
+func recordAuthDecision(decision *AuthDecision) {
+	auditSinkLock.RLock()
+	sink := auditSink
+	auditSinkLock.RUnlock()
+
+	if sink != nil {
+		sink.Record(decision)
+	}
+}
+
+// LogAuditSink logs denied requests at debug level (or info level, if DenialsAtInfo is set), and authorised ones at
+// trace level. Services wanting to see denials without debug logging can opt in with:
+//
+//	localisation.SetAuditSink(localisation.NewCountingAuditSink(localisation.LogAuditSink{DenialsAtInfo: true}))
+type LogAuditSink struct {
+	DenialsAtInfo bool
+}
+
+func (s LogAuditSink) Record(d *AuthDecision) {
+	if d.Authorised {
+		log.Tracef("[HOB authoriser] Authorised %s %s.%s: roles=%v hobs=%v cacheHit=%v", d.MessageID, d.Service,
+			d.Endpoint, d.RolesTried, d.Hobs, d.CacheHit)
+		return
+	}
+	logf := log.Debugf
+	if s.DenialsAtInfo {
+		logf = log.Infof
+	}
+	logf("[HOB authoriser] Denied %s %s.%s: roles=%v hobs=%v deniedHobs=%v discriminated=%v cacheHit=%v err=%v",
+		d.MessageID, d.Service, d.Endpoint, d.RolesTried, d.Hobs, d.DeniedHobs, d.Discriminated, d.CacheHit, d.Err)
+}
+
+// AuditKey identifies an AuditCounts entry. Hob is empty for decisions made without a HOB (eg: a global role).
+type AuditKey struct {
+	Hob        string
+	Role       string
+	Authorised bool
+}
+
+// AuditCounts holds the number of decisions made, per HOB, role and outcome
+type AuditCounts map[AuditKey]uint64
+
+// CountingAuditSink counts decisions per HOB and role, before passing them on to another sink
+type CountingAuditSink struct {
+	next   AuditSink
+	mtx    sync.RWMutex
+	counts AuditCounts
+}
+
+// NewCountingAuditSink returns a CountingAuditSink passing decisions on to next (which may be nil)
+func NewCountingAuditSink(next AuditSink) *CountingAuditSink {
+	return &CountingAuditSink{
+		next:   next,
+		counts: make(AuditCounts),
+	}
+}
+
+func (s *CountingAuditSink) Record(d *AuthDecision) {
+	s.mtx.Lock()
+	if len(d.Hobs) == 0 {
+		s.countRoles("", d.Roles, d.Authorised)
+	} else {
+		denied := make(map[string]bool, len(d.DeniedHobs))
+		for _, hob := range d.DeniedHobs {
+			denied[hob] = true
+		}
+		for _, hob := range d.Hobs {
+			s.countRoles(hob, d.Roles, !denied[hob])
+		}
+	}
+	s.mtx.Unlock()
+
+	if s.next != nil {
+		s.next.Record(d)
+	}
+}
+
+func (s *CountingAuditSink) countRoles(hob string, roles []string, authorised bool) {
+	for _, role := range roles {
+		s.counts[AuditKey{Hob: hob, Role: role, Authorised: authorised}]++
+	}
+}
+
+// Counts returns a snapshot of the counts so far
+func (s *CountingAuditSink) Counts() AuditCounts {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	counts := make(AuditCounts, len(s.counts))
+	for k, v := range s.counts {
+		counts[k] = v
+	}
+	return counts
+}
+
+// Reset zeroes all the counts
+func (s *CountingAuditSink) Reset() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.counts = make(AuditCounts)
+}
+
+func newAuthDecision(req *server.Request, roles []string) *AuthDecision {
+	return &AuthDecision{
+		Time:       time.Now(),
+		MessageID:  req.MessageID(),
+		Service:    req.Service(),
+		Endpoint:   req.Endpoint(),
+		Roles:      roles,
+		RolesTried: append([]string(nil), roles...),
+	}
+}
+
+func (d *AuthDecision) discriminated(cacheHit bool, hobs ...string) {
+	d.Discriminated = true
+	d.CacheHit = cacheHit
+	d.Hobs = uniqueHobs(hobs)
+}
+
+func (d *AuthDecision) tried(roles []string) {
+	for _, role := range roles {
+		if !containsString(d.RolesTried, role) {
+			d.RolesTried = append(d.RolesTried, role)
+		}
+	}
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *AuthDecision) allow() {
+	d.Authorised = true
+	recordAuthDecision(d)
+}
+
+func (d *AuthDecision) deny(err errors.Error, deniedHobs ...string) {
+	d.Authorised = false
+	d.Err = err
+	d.DeniedHobs = uniqueHobs(deniedHobs)
+	recordAuthDecision(d)
+}
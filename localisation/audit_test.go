@@ -0,0 +1,109 @@
+package localisation
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/HailoOSS/platform/server"
+)
+
+// capturingSink keeps every decision recorded
+type capturingSink struct {
+	sync.Mutex
+	decisions []*AuthDecision
+}
+
+func (s *capturingSink) Record(d *AuthDecision) {
+	s.Lock()
+	defer s.Unlock()
+	s.decisions = append(s.decisions, d)
+}
+
+func (s *capturingSink) last() *AuthDecision {
+	s.Lock()
+	defer s.Unlock()
+	return s.decisions[len(s.decisions)-1]
+}
+
+func TestHobAuthoriserAudit(t *testing.T) {
+	sink := &capturingSink{}
+	counter := NewCountingAuditSink(sink)
+	SetAuditSink(counter)
+	defer SetAuditSink(DefaultAuditCounter)
+
+	newAuthoriser := func(granted ...string) *hobAuthoriser {
+		return &hobAuthoriser{
+			roles:          []string{"ADMIN"},
+			authoriserImpl: grantingAuthoriser(granted...),
+			hobCache:       newDiscriminatorCache(StaticDiscriminator("MAN")),
+		}
+	}
+	req := server.NewRequestFromDelivery(amqp.Delivery{MessageId: "1"})
+
+	// Global role: the discriminator isn't consulted
+	assert.Nil(t, newAuthoriser("ADMIN").Authorise(req))
+	d := sink.last()
+	assert.True(t, d.Authorised)
+	assert.False(t, d.Discriminated)
+	assert.Equal(t, []string{"ADMIN"}, d.RolesTried)
+	assert.Empty(t, d.Hobs)
+
+	// HOB role
+	authoriser := newAuthoriser("ADMIN.MAN")
+	assert.Nil(t, authoriser.Authorise(req))
+	d = sink.last()
+	assert.True(t, d.Authorised)
+	assert.True(t, d.Discriminated)
+	assert.False(t, d.CacheHit)
+	assert.Equal(t, []string{"MAN"}, d.Hobs)
+	assert.Equal(t, []string{"ADMIN", "ADMIN.MAN", "ADMIN.*"}, d.RolesTried)
+
+	// Same request again comes from the cache
+	assert.Nil(t, authoriser.Authorise(req))
+	assert.True(t, sink.last().CacheHit)
+
+	// Role in the wrong HOB
+	err := newAuthoriser("ADMIN.LON").Authorise(req)
+	assert.NotNil(t, err)
+	d = sink.last()
+	assert.False(t, d.Authorised)
+	assert.Equal(t, []string{"MAN"}, d.DeniedHobs)
+	assert.Equal(t, err, d.Err)
+
+	assert.Equal(t, AuditCounts{
+		AuditKey{Hob: "", Role: "ADMIN", Authorised: true}:     1,
+		AuditKey{Hob: "MAN", Role: "ADMIN", Authorised: true}:  2,
+		AuditKey{Hob: "MAN", Role: "ADMIN", Authorised: false}: 1,
+	}, counter.Counts())
+
+	counter.Reset()
+	assert.Empty(t, counter.Counts())
+}
+
+func TestMultiHobAuthoriserAudit(t *testing.T) {
+	counter := NewCountingAuditSink(nil)
+	SetAuditSink(counter)
+	defer SetAuditSink(DefaultAuditCounter)
+
+	ha := &multiHobAuthoriser{
+		roles:          []string{"ADMIN"},
+		mode:           RequireAllHobs,
+		authoriserImpl: grantingAuthoriser("ADMIN.LON"),
+		hobsCache: newMultiHobDiscriminatorCache(func(req *server.Request) []string {
+			return []string{"LON", "MAN"}
+		}),
+	}
+	assert.NotNil(t, ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "1"})))
+
+	assert.Equal(t, AuditCounts{
+		AuditKey{Hob: "LON", Role: "ADMIN", Authorised: true}:  1,
+		AuditKey{Hob: "MAN", Role: "ADMIN", Authorised: false}: 1,
+	}, counter.Counts())
+
+	// Nothing breaks without a sink
+	SetAuditSink(nil)
+	assert.NotNil(t, ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "2"})))
+}
@@ -1,9 +1,9 @@
 package localisation
 
 import (
-	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	log "github.com/cihub/seelog"
 	"github.com/golang/groupcache/lru"
@@ -16,63 +16,201 @@ import (
 // an empty string should be returned.
 type HobDiscriminator func(req *server.Request) (hob string)
 
-// cachingHobDiscriminator is a decorator for a HobDiscriminator, memoising the result (per-request) using a LRU cache
-func cachingHobDiscriminator(discriminatorImpl HobDiscriminator) HobDiscriminator {
-	resultCache := lru.New(300)
-	var cacheLock sync.RWMutex // lru isn't concurrency safe
+const (
+	defaultCacheSize = 300
+	defaultCacheTTL  = time.Minute
+)
 
-	return func(req *server.Request) (hob string) {
-		reqId := req.MessageID()
+// AuthoriserOption configures a HOB authoriser
+type AuthoriserOption func(*authoriserOptions)
+
+type authoriserOptions struct {
+	cacheSize      int
+	cacheTTL       time.Duration
+	regions        HobRegions
+	roleAuthoriser func([]string) server.Authoriser
+}
+
+func newAuthoriserOptions(opts []AuthoriserOption) *authoriserOptions {
+	options := &authoriserOptions{
+		cacheSize: defaultCacheSize,
+		cacheTTL:  defaultCacheTTL,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+func (o *authoriserOptions) roleAuthoriserOr(defaultImpl func([]string) server.Authoriser) func([]string) server.Authoriser {
+	if o.roleAuthoriser != nil {
+		return o.roleAuthoriser
+	}
+	return defaultImpl
+}
+
+// RoleAuthoriserImpl replaces the authoriser used to check the caller holds the (HOB-scoped) roles, which is
+// server.RoleAuthoriser or server.SignInRoleAuthoriser by default. This is mostly useful for testing (see the
+// localisation/testing package).
+func RoleAuthoriserImpl(impl func(roles []string) server.Authoriser) AuthoriserOption {
+	return func(o *authoriserOptions) {
+		o.roleAuthoriser = impl
+	}
+}
+
+// CacheSize sets the number of requests whose discriminated HOB(s) are remembered (default 300). Non-positive sizes
+// are ignored.
+func CacheSize(size int) AuthoriserOption {
+	return func(o *authoriserOptions) {
+		if size > 0 {
+			o.cacheSize = size
+		}
+	}
+}
+
+// CacheTTL sets how long a discriminated HOB is remembered for (default 1 minute). A TTL of 0 means entries only
+// leave the cache when evicted to make room for others.
+func CacheTTL(ttl time.Duration) AuthoriserOption {
+	return func(o *authoriserOptions) {
+		o.cacheTTL = ttl
+	}
+}
+
+// CacheStats describes the usage of a HOB authoriser's discriminator cache
+type CacheStats struct {
+	Size        int    // Entries currently held
+	Hits        uint64 // Lookups answered from the cache
+	Misses      uint64 // Lookups that had to call the discriminator (including expired entries)
+	Evictions   uint64 // Entries pushed out to make room for others
+	Expirations uint64 // Entries found to have outlived the TTL
+}
 
-		// Try to get the result from the cache
-		cacheLock.RLock()
-		_hob, ok := resultCache.Get(reqId)
-		cacheLock.RUnlock()
+// CachingAuthoriser is implemented by the authorisers in this package, exposing the stats of their discriminator
+// cache, eg:
+//
+//	if ca, ok := authoriser.(localisation.CachingAuthoriser); ok {
+//		stats := ca.CacheStats()
+//	}
+type CachingAuthoriser interface {
+	server.Authoriser
+	CacheStats() CacheStats
+}
+
+type cacheEntry struct {
+	hob     string
+	expires time.Time // zero if the entry never expires
+}
 
-		if ok && _hob != nil {
+// discriminatorCache memoises the results of a HobDiscriminator (per-request) using a LRU cache, optionally expiring
+// them after a TTL
+type discriminatorCache struct {
+	discriminatorImpl HobDiscriminator
+	ttl               time.Duration
+	now               func() time.Time
+	resultCache       *lru.Cache
+	stats             CacheStats
+	cacheLock         sync.Mutex // lru isn't concurrency safe, and even a Get reorders it
+}
+
+func newDiscriminatorCache(discriminatorImpl HobDiscriminator, opts ...AuthoriserOption) *discriminatorCache {
+	options := newAuthoriserOptions(opts)
+	c := &discriminatorCache{
+		discriminatorImpl: discriminatorImpl,
+		ttl:               options.cacheTTL,
+		now:               time.Now,
+		resultCache:       lru.New(options.cacheSize),
+	}
+	c.resultCache.OnEvicted = func(key lru.Key, value interface{}) {
+		// Only called when making room; expired entries are overwritten rather than removed
+		c.stats.Evictions++
+	}
+	return c
+}
+
+// discriminate returns the HOB for the request, and whether it was found in the cache
+func (c *discriminatorCache) discriminate(req *server.Request) (hob string, hit bool) {
+	reqId := req.MessageID()
+
+	// Try to get the result from the cache
+	c.cacheLock.Lock()
+	if v, ok := c.resultCache.Get(reqId); ok && v != nil {
+		entry := v.(cacheEntry)
+		if entry.expires.IsZero() || c.now().Before(entry.expires) {
 			// Cache hit yey
-			hob = _hob.(string)
-		} else {
-			// It wasn't in the cache; compute it and store it in the cache
-			hob = discriminatorImpl(req)
-			cacheLock.Lock()
-			resultCache.Add(reqId, hob)
-			cacheLock.Unlock()
+			c.stats.Hits++
+			c.cacheLock.Unlock()
+			return entry.hob, true
 		}
+		c.stats.Expirations++
+	}
+	c.stats.Misses++
+	c.cacheLock.Unlock()
+
+	// It wasn't in the cache (or was stale); compute it and store it in the cache
+	hob = c.discriminatorImpl(req)
+	entry := cacheEntry{hob: hob}
+	if c.ttl > 0 {
+		entry.expires = c.now().Add(c.ttl)
+	}
+	c.cacheLock.Lock()
+	c.resultCache.Add(reqId, entry)
+	c.cacheLock.Unlock()
 
+	return hob, false
+}
+
+func (c *discriminatorCache) cacheStats() CacheStats {
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+
+	stats := c.stats
+	stats.Size = c.resultCache.Len()
+	return stats
+}
+
+// cachingHobDiscriminator is a decorator for a HobDiscriminator, memoising the result (per-request) using a LRU cache
+func cachingHobDiscriminator(discriminatorImpl HobDiscriminator) HobDiscriminator {
+	cache := newDiscriminatorCache(discriminatorImpl)
+
+	return func(req *server.Request) (hob string) {
+		hob, _ = cache.discriminate(req)
 		return hob
 	}
 }
 
 type hobAuthoriser struct {
-	roles            []string
-	authoriserImpl   func([]string) server.Authoriser
-	hobDiscriminator HobDiscriminator
+	roles          []string
+	regions        HobRegions
+	authoriserImpl func([]string) server.Authoriser
+	hobCache       *discriminatorCache
+}
+
+// CacheStats returns the stats of the authoriser's discriminator cache
+func (ha *hobAuthoriser) CacheStats() CacheStats {
+	return ha.hobCache.cacheStats()
 }
 
 func (ha *hobAuthoriser) Authorise(req *server.Request) errors.Error {
+	decision := newAuthDecision(req, ha.roles)
+
 	// Try and authorise *without* consulting the hobDiscriminator (as it may potentially have to do lots of work that
 	// we can save ourselves from). If they have a more global role than the HOB-specific one, then party on.
 	if err := ha.authoriserImpl(ha.roles).Authorise(req); err == nil {
 		log.Tracef("[HOB authoriser] Matched non-HOB-specific role; not calling the HOB discriminator")
+		decision.allow()
 		return nil
 	}
 
-	hob := ha.hobDiscriminator(req)
-	var reqRoles []string
-
-	if hob == "" {
-		reqRoles = ha.roles
-	} else {
-		reqRoles = make([]string, len(ha.roles))
-		for i, role := range ha.roles {
-			reqRoles[i] = fmt.Sprintf("%s.%s", role, hob)
-		}
-	}
+	hob, hit := ha.hobCache.discriminate(req)
+	decision.discriminated(hit, hob)
 
+	reqRoles := scopedRoles(ha.roles, hob, ha.regions)
+	decision.tried(reqRoles)
 	err := ha.authoriserImpl(reqRoles).Authorise(req)
 	if err != nil {
-		log.Debugf("[HOB authoriser] Failed to authorise in HOB %s", hob)
+		decision.deny(err, hob)
+	} else {
+		decision.allow()
 	}
 	return err
 }
@@ -82,7 +220,7 @@ func (ha *hobAuthoriser) Authorise(req *server.Request) errors.Error {
 func validateHobRoles(roles []string) {
 	for _, role := range roles {
 		if strings.HasSuffix(role, ".*") {
-			panic("HOB-specific roles may not have a .* suffix")
+			panic("HOB-specific roles may not have a .* suffix (use the Regions option for region-wide grants)")
 		}
 	}
 }
@@ -90,23 +228,33 @@ func validateHobRoles(roles []string) {
 // HobRoleAuthoriser requires a service or user calling an endpoint to have ANY of the roles passed, which will be
 // scoped to a particular HOB (as determined by hobDiscriminator). Following the convention of a HOB being the last
 // portion of a role, the matched role(s) will be of the form "{{ role }}.{{ HOB }}" (or just "{{ role }}" if the
-// hobDiscriminator returns "").
-func HobRoleAuthoriser(roles []string, hobDiscriminator HobDiscriminator) server.Authoriser {
+// hobDiscriminator returns ""). A role granted for all HOBs ("{{ role }}.*") is always accepted, even if no Regions
+// are configured. The discriminator's results are cached per-request, which can be tuned with opts. Panics if any of
+// the roles end with ".*", so a misconfigured service fails at startup.
+func HobRoleAuthoriser(roles []string, hobDiscriminator HobDiscriminator, opts ...AuthoriserOption) server.Authoriser {
+	validateHobRoles(roles)
+	options := newAuthoriserOptions(opts)
 	return &hobAuthoriser{
-		roles:            roles,
-		authoriserImpl:   server.RoleAuthoriser,
-		hobDiscriminator: cachingHobDiscriminator(hobDiscriminator),
+		roles:          roles,
+		regions:        options.regions,
+		authoriserImpl: options.roleAuthoriserOr(server.RoleAuthoriser),
+		hobCache:       newDiscriminatorCache(hobDiscriminator, opts...),
 	}
 }
 
 // SignInHobRoleAuthoriser requires a real person signed in calling an endpoint to have ANY of the passed roles, which
 // will be scoped to a particular HOB (as determined by hobDiscriminator). Following the convention of a HOB being the
 // last portion of a role, the matched role(s) will be of the form "{{ role }}.{{ HOB }}" (or just "{{ role }}" if the
-// hobDiscriminator returns "").
-func SignInHobRoleAuthoriser(roles []string, hobDiscriminator HobDiscriminator) server.Authoriser {
+// hobDiscriminator returns ""). A role granted for all HOBs ("{{ role }}.*") is always accepted, even if no Regions
+// are configured. The discriminator's results are cached per-request, which can be tuned with opts. Panics if any of
+// the roles end with ".*", so a misconfigured service fails at startup.
+func SignInHobRoleAuthoriser(roles []string, hobDiscriminator HobDiscriminator, opts ...AuthoriserOption) server.Authoriser {
+	validateHobRoles(roles)
+	options := newAuthoriserOptions(opts)
 	return &hobAuthoriser{
-		roles:            roles,
-		authoriserImpl:   server.SignInRoleAuthoriser,
-		hobDiscriminator: cachingHobDiscriminator(hobDiscriminator),
+		roles:          roles,
+		regions:        options.regions,
+		authoriserImpl: options.roleAuthoriserOr(server.SignInRoleAuthoriser),
+		hobCache:       newDiscriminatorCache(hobDiscriminator, opts...),
 	}
 }
@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/streadway/amqp"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/HailoOSS/platform/server"
+	"github.com/HailoOSS/protobuf/proto"
+
+	loctesting "github.com/HailoOSS/go-hailo-lib/localisation/testing"
 )
 
 func TestCachingHobDiscriminator(t *testing.T) {
@@ -49,5 +53,129 @@ func TestCachingHobDiscriminator(t *testing.T) {
 	assert.NotEqual(t, "result-1", cachedDiscriminator(testReq), "LRU isn't capped at 300 results")
 }
 
-// @TODO: Need to properly test the actual authorisers, but this isn't possible at the moment given there's no request
-// mocking functionality
+func TestDiscriminatorCacheOptions(t *testing.T) {
+	times := 0
+	cache := newDiscriminatorCache(func(req *server.Request) string {
+		times++
+		return fmt.Sprintf("result-%d", times)
+	}, CacheSize(2), CacheTTL(time.Minute))
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	req := func(id string) *server.Request {
+		return server.NewRequestFromDelivery(amqp.Delivery{MessageId: id})
+	}
+
+	hob, hit := cache.discriminate(req("1"))
+	assert.Equal(t, "result-1", hob)
+	assert.False(t, hit)
+	hob, hit = cache.discriminate(req("1"))
+	assert.Equal(t, "result-1", hob)
+	assert.True(t, hit)
+
+	// Entries expire after the TTL
+	now = now.Add(time.Minute)
+	hob, hit = cache.discriminate(req("1"))
+	assert.Equal(t, "result-2", hob)
+	assert.False(t, hit)
+
+	// And are evicted beyond the size
+	cache.discriminate(req("2"))
+	cache.discriminate(req("3"))
+	_, hit = cache.discriminate(req("1"))
+	assert.False(t, hit)
+
+	assert.Equal(t, CacheStats{
+		Size:        2,
+		Hits:        1,
+		Misses:      5,
+		Evictions:   2,
+		Expirations: 1,
+	}, cache.cacheStats())
+}
+
+func TestDiscriminatorCacheNoTTL(t *testing.T) {
+	cache := newDiscriminatorCache(func(req *server.Request) string {
+		return "LON"
+	}, CacheTTL(0), CacheSize(-1))
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	testReq := server.NewRequestFromDelivery(amqp.Delivery{MessageId: "1"})
+
+	cache.discriminate(testReq)
+	now = now.Add(24 * time.Hour)
+	_, hit := cache.discriminate(testReq)
+	assert.True(t, hit, "Entry expired without a TTL")
+	assert.Equal(t, defaultCacheSize, cache.resultCache.MaxEntries)
+}
+
+func TestHobRoleAuthoriserCacheStats(t *testing.T) {
+	authoriser := HobRoleAuthoriser([]string{"ADMIN"}, StaticDiscriminator("LON"), CacheSize(10))
+	ca, ok := authoriser.(CachingAuthoriser)
+	if assert.True(t, ok, "HobRoleAuthoriser doesn't expose its cache stats") {
+		assert.Equal(t, CacheStats{}, ca.CacheStats())
+	}
+
+	authoriser = MultiHobRoleAuthoriser([]string{"ADMIN"}, RequireAllHobs, func(req *server.Request) []string {
+		return nil
+	})
+	_, ok = authoriser.(CachingAuthoriser)
+	assert.True(t, ok, "MultiHobRoleAuthoriser doesn't expose its cache stats")
+}
+
+func TestHobRoleAuthoriser(t *testing.T) {
+	regions := HobRegions{"UK": {"LON", "MAN"}}
+	testCases := []struct {
+		desc       string
+		held       []string
+		hob        string
+		signedIn   bool
+		authorised bool
+	}{
+		{"global role", []string{"ADMIN"}, "LON", true, true},
+		{"HOB role", []string{"ADMIN.LON"}, "LON", true, true},
+		{"other HOB's role", []string{"ADMIN.MAN"}, "LON", true, false},
+		{"region role", []string{"ADMIN.UK.*"}, "MAN", true, true},
+		{"region role outside region", []string{"ADMIN.UK.*"}, "DUB", true, false},
+		{"wildcard role", []string{"ADMIN.*"}, "DUB", true, true},
+		{"HOB role without a HOB", []string{"ADMIN.LON"}, "", true, false},
+		{"other role", []string{"CUSTOMER.LON"}, "LON", true, false},
+		{"no roles", nil, "LON", true, false},
+		{"not signed in", []string{"ADMIN.LON"}, "LON", false, false},
+	}
+
+	authoriser := SignInHobRoleAuthoriser([]string{"ADMIN"}, HeaderDiscriminator(loctesting.HobHeader),
+		Regions(regions), RoleAuthoriserImpl(loctesting.SignInRoleAuthoriser))
+	for _, tc := range testCases {
+		builder := loctesting.NewRequest().WithRoles(tc.held...).WithHob(tc.hob)
+		if tc.signedIn {
+			builder.WithSession("abc123")
+		}
+		err := authoriser.Authorise(builder.Build())
+		assert.Equal(t, tc.authorised, err == nil, "Unexpected result for %s: %v", tc.desc, err)
+	}
+
+	// Without regions, only the HOB itself is checked
+	authoriser = HobRoleAuthoriser([]string{"ADMIN"}, HeaderDiscriminator(loctesting.HobHeader),
+		RoleAuthoriserImpl(loctesting.RoleAuthoriser))
+	assert.Nil(t, authoriser.Authorise(loctesting.NewRequest().WithRoles("ADMIN.LON").WithHob("LON").Build()))
+	assert.NotNil(t, authoriser.Authorise(loctesting.NewRequest().WithRoles("ADMIN.UK.*").WithHob("LON").Build()))
+}
+
+func TestHobRoleAuthoriserProtoField(t *testing.T) {
+	authoriser := HobRoleAuthoriser([]string{"ADMIN"}, IDFieldDiscriminator(&testRequestMessage{}, "driver_id"),
+		RoleAuthoriserImpl(loctesting.RoleAuthoriser))
+
+	req := loctesting.NewRequest().
+		WithRoles("ADMIN.MAN").
+		WithPayload(&testRequestMessage{DriverId: proto.String("MAN1234")}).
+		Build()
+	assert.Nil(t, authoriser.Authorise(req))
+
+	req = loctesting.NewRequest().
+		WithRoles("ADMIN.LON").
+		WithPayload(&testRequestMessage{DriverId: proto.String("MAN1234")}).
+		Build()
+	assert.NotNil(t, authoriser.Authorise(req))
+}
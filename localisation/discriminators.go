@@ -0,0 +1,120 @@
+package localisation
+
+import (
+	"reflect"
+	"strings"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/HailoOSS/platform/server"
+	"github.com/HailoOSS/protobuf/proto"
+)
+
+// ProtoFieldDiscriminator returns a HobDiscriminator that unmarshals the request into a new message of the same type
+// as template, and reads the HOB from the named field. The field may be given by its proto name ("hob"), Go name
+// ("Hob"), or as a dotted path into nested messages ("job.hob").
+func ProtoFieldDiscriminator(template proto.Message, field string) HobDiscriminator {
+	return func(req *server.Request) string {
+		return readProtoField(req, template, field)
+	}
+}
+
+// IDFieldDiscriminator returns a HobDiscriminator that reads an ID (of the form LON1234) from the named field of the
+// request, as ProtoFieldDiscriminator does, and extracts the HOB from it
+func IDFieldDiscriminator(template proto.Message, field string) HobDiscriminator {
+	return func(req *server.Request) string {
+		return ExtractHobFromID(readProtoField(req, template, field))
+	}
+}
+
+// HeaderDiscriminator returns a HobDiscriminator that reads the HOB from the named request header
+func HeaderDiscriminator(header string) HobDiscriminator {
+	return func(req *server.Request) string {
+		hob, _ := req.Headers()[header].(string)
+		return hob
+	}
+}
+
+// StaticDiscriminator returns a HobDiscriminator that always returns the given HOB
+func StaticDiscriminator(hob string) HobDiscriminator {
+	return func(req *server.Request) string {
+		return hob
+	}
+}
+
+// First returns a HobDiscriminator that tries each of the discriminators in turn, returning the first HOB found
+func First(discriminators ...HobDiscriminator) HobDiscriminator {
+	return func(req *server.Request) string {
+		for _, discriminator := range discriminators {
+			if hob := discriminator(req); hob != "" {
+				return hob
+			}
+		}
+		return ""
+	}
+}
+
+// Fallback returns a HobDiscriminator that returns the given HOB if the discriminator can't determine one
+func Fallback(discriminator HobDiscriminator, hob string) HobDiscriminator {
+	return First(discriminator, StaticDiscriminator(hob))
+}
+
+func readProtoField(req *server.Request, template proto.Message, field string) string {
+	msg := reflect.New(reflect.TypeOf(template).Elem()).Interface().(proto.Message)
+	if err := req.Unmarshal(msg); err != nil {
+		log.Debugf("[HOB discriminator] Failed to unmarshal request into %T: %v", msg, err)
+		return ""
+	}
+	return protoFieldValue(msg, field)
+}
+
+// protoFieldValue reads a string from the (possibly nested) field of a proto message; "" if not set or not a string
+func protoFieldValue(msg interface{}, path string) string {
+	v := reflect.ValueOf(msg)
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return ""
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return ""
+		}
+		if v = protoField(v, name); !v.IsValid() {
+			return ""
+		}
+	}
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String {
+		return ""
+	}
+	return v.String()
+}
+
+func protoField(v reflect.Value, name string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == name || protoTagName(f.Tag.Get("protobuf")) == name {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// protoTagName extracts the name from a tag like `protobuf:"bytes,1,opt,name=city_id"`
+func protoTagName(tag string) string {
+	for _, part := range strings.Split(tag, ",") {
+		if strings.HasPrefix(part, "name=") {
+			return strings.TrimPrefix(part, "name=")
+		}
+	}
+	return ""
+}
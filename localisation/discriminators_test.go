@@ -0,0 +1,112 @@
+package localisation
+
+import (
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/HailoOSS/platform/server"
+	"github.com/HailoOSS/protobuf/proto"
+
+	loctesting "github.com/HailoOSS/go-hailo-lib/localisation/testing"
+)
+
+type testJobMessage struct {
+	Hob              *string `protobuf:"bytes,1,opt,name=hob" json:"hob,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+type testRequestMessage struct {
+	CityId           *string         `protobuf:"bytes,1,opt,name=city_id" json:"city_id,omitempty"`
+	DriverId         *string         `protobuf:"bytes,2,opt,name=driver_id" json:"driver_id,omitempty"`
+	Count            *int32          `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+	Job              *testJobMessage `protobuf:"bytes,4,opt,name=job" json:"job,omitempty"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *testRequestMessage) Reset()         { *m = testRequestMessage{} }
+func (m *testRequestMessage) String() string { return proto.CompactTextString(m) }
+func (*testRequestMessage) ProtoMessage()    {}
+
+func TestProtoFieldValue(t *testing.T) {
+	msg := &testRequestMessage{
+		CityId:   proto.String("LON"),
+		DriverId: proto.String("MAN1234"),
+		Count:    proto.Int32(3),
+		Job:      &testJobMessage{Hob: proto.String("DUB")},
+	}
+
+	testCases := []struct {
+		field    string
+		expected string
+	}{
+		{"city_id", "LON"},
+		{"CityId", "LON"},
+		{"driver_id", "MAN1234"},
+		{"job.hob", "DUB"},
+		{"Job.Hob", "DUB"},
+		{"count", ""},
+		{"missing", ""},
+		{"job.missing", ""},
+		{"city_id.hob", ""},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, protoFieldValue(msg, tc.field), "Incorrect value for field %s", tc.field)
+	}
+
+	assert.Equal(t, "", protoFieldValue(&testRequestMessage{}, "city_id"))
+	assert.Equal(t, "", protoFieldValue(&testRequestMessage{}, "job.hob"))
+}
+
+func TestProtoFieldDiscriminator(t *testing.T) {
+	req := loctesting.NewRequest().WithPayload(&testRequestMessage{
+		CityId: proto.String("LON"),
+		Job:    &testJobMessage{Hob: proto.String("DUB")},
+	}).Build()
+
+	assert.Equal(t, "LON", ProtoFieldDiscriminator(&testRequestMessage{}, "city_id")(req))
+	assert.Equal(t, "DUB", ProtoFieldDiscriminator(&testRequestMessage{}, "job.hob")(req))
+	assert.Equal(t, "", ProtoFieldDiscriminator(&testRequestMessage{}, "driver_id")(req))
+
+	// Requests that can't be unmarshalled have no HOB
+	garbled := server.NewRequestFromDelivery(amqp.Delivery{Body: []byte{0xff, 0xff}})
+	assert.Equal(t, "", ProtoFieldDiscriminator(&testRequestMessage{}, "city_id")(garbled))
+}
+
+func TestIDFieldDiscriminator(t *testing.T) {
+	req := loctesting.NewRequest().WithPayload(&testRequestMessage{
+		CityId:   proto.String("1234"),
+		DriverId: proto.String("MAN1234"),
+	}).Build()
+
+	assert.Equal(t, "MAN", IDFieldDiscriminator(&testRequestMessage{}, "driver_id")(req))
+	assert.Equal(t, "", IDFieldDiscriminator(&testRequestMessage{}, "city_id")(req))
+	assert.Equal(t, "", IDFieldDiscriminator(&testRequestMessage{}, "job.hob")(req))
+}
+
+func TestHeaderDiscriminator(t *testing.T) {
+	req := server.NewRequestFromDelivery(amqp.Delivery{
+		Headers: amqp.Table{"hob": "LON", "count": int32(3)},
+	})
+
+	assert.Equal(t, "LON", HeaderDiscriminator("hob")(req))
+	assert.Equal(t, "", HeaderDiscriminator("count")(req))
+	assert.Equal(t, "", HeaderDiscriminator("missing")(req))
+}
+
+func TestDiscriminatorCombinators(t *testing.T) {
+	req := server.NewRequestFromDelivery(amqp.Delivery{
+		Headers: amqp.Table{"hob": "LON"},
+	})
+	none := StaticDiscriminator("")
+
+	assert.Equal(t, "LON", First(none, HeaderDiscriminator("hob"), StaticDiscriminator("MAN"))(req))
+	assert.Equal(t, "MAN", First(none, HeaderDiscriminator("city"), StaticDiscriminator("MAN"))(req))
+	assert.Equal(t, "", First(none, HeaderDiscriminator("city"))(req))
+	assert.Equal(t, "", First()(req))
+
+	assert.Equal(t, "LON", Fallback(HeaderDiscriminator("hob"), "MAN")(req))
+	assert.Equal(t, "MAN", Fallback(HeaderDiscriminator("city"), "MAN")(req))
+}
@@ -2,8 +2,11 @@ package hob
 
 import (
 	"encoding/json"
+	"io"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 type mockConfigService struct{}
@@ -51,14 +54,17 @@ func (m *mockConfigService) Multiconfig(hobs, hobHashes, serviceTypesHashes []st
 }
 
 func setup() {
-	h2HobService = &mockConfigService{}
-	memoryCacheImpl = make(map[string]*HobData)
+	SetHobService(&mockConfigService{})
+	if c, ok := Cache.(io.Closer); ok {
+		c.Close()
+	}
+	Cache = MemoryHobsCache()
 }
 
 func TestCache(t *testing.T) {
 	setup()
-	h, err := h2HobService.ReadHob("MCK")
-	s, err := h2HobService.ReadServiceTypes("MCK")
+	h, err := defaultHobService().ReadHob("MCK")
+	s, err := defaultHobService().ReadServiceTypes("MCK")
 	hobData1 := &HobData{
 		Hob:              h,
 		HobHash:          "aaa",
@@ -122,3 +128,97 @@ type HobMockNewField struct {
 	FastestFirst bool      `json:"fastestFirst"` // Specified whether the "fastest first" feature in the passenger app is enabled
 	A_NEW_FIELD  bool      `json:"anewfield"`
 }
+
+func TestCacheInstances(t *testing.T) {
+	lon := MemoryHobsCache(CacheHobService(NewStaticHobService(&HobData{Hob: &Hob{Code: "LON", Name: "London"}})))
+	defer lon.(io.Closer).Close()
+	mad := MemoryHobsCache(CacheHobService(NewStaticHobService(&HobData{Hob: &Hob{Code: "LON", Name: "Not London"}})))
+	defer mad.(io.Closer).Close()
+
+	assert.NoError(t, lon.(*cache).readMulticonfig([]string{"LON"}, []string{""}, []string{""}))
+	if h := lon.ReadHob("LON"); assert.NotNil(t, h) {
+		assert.Equal(t, "London", h.Name)
+	}
+	assert.Empty(t, mad.(*cache).hobs)
+
+	assert.NoError(t, mad.(*cache).readMulticonfig([]string{"LON"}, []string{""}, []string{""}))
+	if h := mad.ReadHob("LON"); assert.NotNil(t, h) {
+		assert.Equal(t, "Not London", h.Name)
+	}
+	assert.Equal(t, "London", lon.ReadHob("LON").Name)
+}
+
+// signallingConfigService sends on loads whenever Multiconfig is called, blocking it until received
+type signallingConfigService struct {
+	HobService
+	loads chan struct{}
+}
+
+func (s *signallingConfigService) Multiconfig(hobs, hobHashes, serviceTypesHashes []string) (newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes []string, err error) {
+	s.loads <- struct{}{}
+	return s.HobService.Multiconfig(hobs, hobHashes, serviceTypesHashes)
+}
+
+func TestGetHobMissUsesCacheService(t *testing.T) {
+	setup()
+	defer setup()
+	Cache = MemoryHobsCache(CacheHobService(NewStaticHobService(&HobData{
+		Hob:          &Hob{Code: "LON", Name: "London"},
+		ServiceTypes: ServiceTypes{"a": &ServiceType{Id: "a"}},
+	})))
+
+	// Misses are read from the cache's hob service, rather than the one installed by SetHobService
+	h, err := GetHob("LON")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "London", h.Name)
+	}
+	serviceTypes, err := GetServiceTypes("LON")
+	assert.NoError(t, err)
+	assert.Contains(t, serviceTypes, "a")
+}
+
+func TestCacheInvalidOptions(t *testing.T) {
+	for _, period := range []time.Duration{0, -time.Second} {
+		c := MemoryHobsCache(ReloadPeriod(period, -time.Second), TriggerReloadJitter(-time.Second)).(*cache)
+		assert.Equal(t, forceReloadPeriod, c.reloadPeriod)
+		assert.Equal(t, forceReloadJitter, c.reloadJitter)
+		assert.Equal(t, triggerReloadJitter, c.triggerJitter)
+
+		// Reloading starts (without the ticker panicking) and stops
+		c.initRefresher.Do(c.scheduleReloadPeriodically)
+		c.Close()
+		<-c.stopped
+	}
+}
+
+func TestCacheClose(t *testing.T) {
+	service := &signallingConfigService{
+		HobService: NewStaticHobService(&HobData{Hob: &Hob{Code: "MCK", Name: "Mock"}}),
+		loads:      make(chan struct{}),
+	}
+	c := MemoryHobsCache(CacheHobService(service), ReloadPeriod(time.Millisecond, 0)).(*cache)
+	sub := c.Subscribe(10)
+
+	c.ReadHob("MCK") // Starts reloading
+	<-service.loads  // The load of MCK...
+	<-service.loads  // ...and a periodic reload
+	c.Close()
+	c.Close()
+
+	// Reloading stops, once any load in progress has finished
+	timeout := time.After(5 * time.Second)
+	for stopped := false; !stopped; {
+		select {
+		case <-service.loads:
+		case <-c.stopped:
+			stopped = true
+		case <-timeout:
+			t.Fatal("Timed out waiting for reloading to stop")
+		}
+	}
+
+	// Subscriptions are closed once drained, and loaded HOBs can still be read
+	for range sub.C {
+	}
+	assert.NotNil(t, c.ReadHob("MCK"))
+}
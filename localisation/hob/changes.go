@@ -0,0 +1,250 @@
+package hob
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ChangeType identifies the kind of a ChangeEvent
+type ChangeType int
+
+const (
+	HobAdded ChangeType = iota
+	HobUpdated
+	ServiceTypeAdded
+	ServiceTypeRemoved
+	ServiceTypeChanged
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case HobAdded:
+		return "HOB_ADDED"
+	case HobUpdated:
+		return "HOB_UPDATED"
+	case ServiceTypeAdded:
+		return "SERVICE_TYPE_ADDED"
+	case ServiceTypeRemoved:
+		return "SERVICE_TYPE_REMOVED"
+	case ServiceTypeChanged:
+		return "SERVICE_TYPE_CHANGED"
+	}
+	return fmt.Sprintf("ChangeType(%d)", int(t))
+}
+
+// FieldChange is a field of a HOB or service type which changed, named as in its JSON
+type FieldChange struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// ChangeEvent describes a change to a HOB or one of its service types, as loaded by the cache
+type ChangeEvent struct {
+	Type        ChangeType
+	Hob         string
+	ServiceType string        // The service type's ID (service type events only)
+	Changes     []FieldChange // The fields which changed (HobUpdated and ServiceTypeChanged only)
+	Err         error         // Why the HOB's new config is invalid, if it is (HobAdded and HobUpdated only)
+}
+
+func (e ChangeEvent) String() string {
+	fields := make([]string, len(e.Changes))
+	for i, change := range e.Changes {
+		fields[i] = change.Field
+	}
+	return fmt.Sprintf("%s hob:%s serviceType:%s fields:%v", e.Type, e.Hob, e.ServiceType, fields)
+}
+
+// ChangeNotifier is implemented by caches which can notify subscribers of changes
+type ChangeNotifier interface {
+	Subscribe(bufferSize int) *Subscription
+}
+
+// Subscribe subscribes to changes loaded by Cache, returning an error if it doesn't support notifications
+func Subscribe(bufferSize int) (*Subscription, error) {
+	notifier, ok := Cache.(ChangeNotifier)
+	if !ok {
+		return nil, fmt.Errorf("HOB cache %T doesn't support change notifications", Cache)
+	}
+	return notifier.Subscribe(bufferSize), nil
+}
+
+// Subscription receives ChangeEvents on C until closed. Events are delivered without blocking the cache, so are
+// dropped (and counted by Dropped) if the subscriber falls more than the buffer size behind.
+type Subscription struct {
+	C <-chan ChangeEvent
+
+	c         chan ChangeEvent
+	mtx       sync.Mutex
+	closed    bool
+	dropped   uint64
+	unsubFunc func(*Subscription)
+}
+
+func newSubscription(bufferSize int, unsubFunc func(*Subscription)) *Subscription {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+	c := make(chan ChangeEvent, bufferSize)
+	return &Subscription{
+		C:         c,
+		c:         c,
+		unsubFunc: unsubFunc,
+	}
+}
+
+// Close unsubscribes, closing C
+func (s *Subscription) Close() {
+	s.mtx.Lock()
+	if s.closed {
+		s.mtx.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.c)
+	s.mtx.Unlock()
+
+	s.unsubFunc(s)
+}
+
+// Dropped returns the number of events dropped because the subscriber wasn't keeping up
+func (s *Subscription) Dropped() uint64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.dropped
+}
+
+func (s *Subscription) deliver(event ChangeEvent) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.c <- event:
+	default:
+		s.dropped++
+	}
+}
+
+// subscribers is a set of subscriptions, which can be embedded in a cache
+type subscribers struct {
+	mtx  sync.RWMutex
+	subs map[*Subscription]bool
+}
+
+func (s *subscribers) Subscribe(bufferSize int) *Subscription {
+	sub := newSubscription(bufferSize, s.unsubscribe)
+	s.mtx.Lock()
+	if s.subs == nil {
+		s.subs = make(map[*Subscription]bool)
+	}
+	s.subs[sub] = true
+	s.mtx.Unlock()
+	return sub
+}
+
+func (s *subscribers) unsubscribe(sub *Subscription) {
+	s.mtx.Lock()
+	delete(s.subs, sub)
+	s.mtx.Unlock()
+}
+
+func (s *subscribers) publish(events []ChangeEvent) {
+	if len(events) == 0 {
+		return
+	}
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	for _, event := range events {
+		for sub := range s.subs {
+			sub.deliver(event)
+		}
+	}
+}
+
+// hobChanges returns the events describing the change from one version of a HOB's data to another (either of which
+// may be nil)
+func hobChanges(code string, oldHob, newHob *Hob, oldServiceTypes, newServiceTypes ServiceTypes,
+	serviceTypesLoaded bool) []ChangeEvent {
+
+	events := make([]ChangeEvent, 0)
+	switch {
+	case oldHob == nil && newHob != nil:
+		events = append(events, ChangeEvent{Type: HobAdded, Hob: code})
+	case oldHob != nil && newHob != nil:
+		if changes := diffFields(*oldHob, *newHob); len(changes) > 0 {
+			events = append(events, ChangeEvent{Type: HobUpdated, Hob: code, Changes: changes})
+		}
+	}
+
+	if !serviceTypesLoaded {
+		return events
+	}
+
+	ids := make([]string, 0, len(oldServiceTypes)+len(newServiceTypes))
+	for id := range oldServiceTypes {
+		ids = append(ids, id)
+	}
+	for id := range newServiceTypes {
+		if _, ok := oldServiceTypes[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		oldST, newST := oldServiceTypes[id], newServiceTypes[id]
+		switch {
+		case oldST == nil && newST != nil:
+			events = append(events, ChangeEvent{Type: ServiceTypeAdded, Hob: code, ServiceType: id})
+		case oldST != nil && newST == nil:
+			events = append(events, ChangeEvent{Type: ServiceTypeRemoved, Hob: code, ServiceType: id})
+		case oldST != nil && newST != nil:
+			if changes := diffFields(*oldST, *newST); len(changes) > 0 {
+				events = append(events, ChangeEvent{Type: ServiceTypeChanged, Hob: code, ServiceType: id,
+					Changes: changes})
+			}
+		}
+	}
+	return events
+}
+
+// withValidationErr sets the HOB events' Err to the HOB's validation error
+func withValidationErr(events []ChangeEvent, err error) []ChangeEvent {
+	for i := range events {
+		if events[i].Type == HobAdded || events[i].Type == HobUpdated {
+			events[i].Err = err
+		}
+	}
+	return events
+}
+
+// diffFields compares the exported fields of two structs of the same type
+func diffFields(oldValue, newValue interface{}) []FieldChange {
+	changes := make([]FieldChange, 0)
+	o, n := reflect.ValueOf(oldValue), reflect.ValueOf(newValue)
+	t := o.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // Unexported
+		}
+		of, nf := o.Field(i).Interface(), n.Field(i).Interface()
+		if !reflect.DeepEqual(of, nf) {
+			changes = append(changes, FieldChange{Field: jsonName(field), Old: of, New: nf})
+		}
+	}
+	return changes
+}
+
+func jsonName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,91 @@
+package hob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// staticConfigService returns fixed configs for MCK from Multiconfig
+type staticConfigService struct {
+	mockConfigService
+	hob, serviceTypes string
+}
+
+func (s *staticConfigService) Multiconfig(hobs, hobHashes, serviceTypesHashes []string) (newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes []string, err error) {
+	return []string{"MCK"}, []string{s.hob}, []string{""}, []string{s.serviceTypes}, []string{""}, nil
+}
+
+func receive(t *testing.T, sub *Subscription) []ChangeEvent {
+	events := make([]ChangeEvent, 0)
+	for {
+		select {
+		case event := <-sub.C:
+			events = append(events, event)
+		case <-time.After(10 * time.Millisecond):
+			return events
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	service := &staticConfigService{
+		hob:          `{"code":"MCK","name":"Mock","timezone":"Europe/London"}`,
+		serviceTypes: `{"a":{"id":"a","name":"A"},"b":{"id":"b","name":"B"}}`,
+	}
+	c := MemoryHobsCache(CacheHobService(service)).(*cache)
+	defer c.Close()
+	sub := c.Subscribe(10)
+	defer sub.Close()
+
+	assert.NoError(t, c.readMulticonfig([]string{"MCK"}, []string{""}, []string{""}))
+	events := receive(t, sub)
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, ChangeEvent{Type: HobAdded, Hob: "MCK"}, events[0])
+		assert.Equal(t, ChangeEvent{Type: ServiceTypeAdded, Hob: "MCK", ServiceType: "a"}, events[1])
+		assert.Equal(t, ChangeEvent{Type: ServiceTypeAdded, Hob: "MCK", ServiceType: "b"}, events[2])
+	}
+
+	// Reloading the same config changes nothing
+	assert.NoError(t, c.readMulticonfig([]string{"MCK"}, []string{""}, []string{""}))
+	assert.Empty(t, receive(t, sub))
+
+	service.hob = `{"code":"MCK","name":"Mock City","currency":"GBP","timezone":"Europe/London"}`
+	service.serviceTypes = `{"a":{"id":"a","name":"A","maxFare":100},"c":{"id":"c","name":"C"}}`
+	assert.NoError(t, c.readMulticonfig([]string{"MCK"}, []string{""}, []string{""}))
+	events = receive(t, sub)
+	if assert.Len(t, events, 4) {
+		assert.Equal(t, HobUpdated, events[0].Type)
+		assert.Equal(t, []FieldChange{
+			{Field: "name", Old: "Mock", New: "Mock City"},
+			{Field: "currency", Old: "", New: "GBP"},
+		}, events[0].Changes)
+		assert.Equal(t, ServiceTypeChanged, events[1].Type)
+		assert.Equal(t, "a", events[1].ServiceType)
+		assert.Equal(t, []FieldChange{{Field: "maxFare", Old: 0.0, New: 100.0}}, events[1].Changes)
+		assert.Equal(t, ChangeEvent{Type: ServiceTypeRemoved, Hob: "MCK", ServiceType: "b"}, events[2])
+		assert.Equal(t, ChangeEvent{Type: ServiceTypeAdded, Hob: "MCK", ServiceType: "c"}, events[3])
+	}
+
+	// A HOB that's unchanged (empty config) with no service types loaded produces no events
+	service.hob, service.serviceTypes = "", ""
+	assert.NoError(t, c.readMulticonfig([]string{"MCK"}, []string{""}, []string{""}))
+	assert.Empty(t, receive(t, sub))
+}
+
+func TestSubscriptionDropsWhenFull(t *testing.T) {
+	s := &subscribers{}
+	sub := s.Subscribe(1)
+	s.publish([]ChangeEvent{{Type: HobAdded, Hob: "LON"}, {Type: HobAdded, Hob: "MAD"}})
+	assert.Equal(t, uint64(1), sub.Dropped())
+	assert.Equal(t, "LON", (<-sub.C).Hob)
+
+	sub.Close()
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	assert.Empty(t, s.subs)
+	// Closed subscriptions aren't delivered to, and can be closed again
+	sub.deliver(ChangeEvent{Type: HobAdded, Hob: "DUB"})
+	sub.Close()
+}
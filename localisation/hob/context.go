@@ -0,0 +1,166 @@
+package hob
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// ContextHobsCache is implemented by caches which can wait for a HOB to be loaded
+type ContextHobsCache interface {
+	ReadHobContext(ctx context.Context, hob string) (*Hob, error)
+}
+
+// GetHobContext returns the HOB, waiting (until the context is done) for it to be loaded into the cache if it isn't
+// already, rather than reading it directly from the hob service as GetHob does. Concurrent calls for the same HOB
+// share a single wait.
+func GetHobContext(ctx context.Context, hob string) (*Hob, error) {
+	if c, ok := Cache.(ContextHobsCache); ok {
+		return c.ReadHobContext(ctx, hob)
+	}
+	return GetHob(hob)
+}
+
+// loadResult is the outcome of a multiconfig load, broadcast to anything waiting for a HOB
+type loadResult struct {
+	hobs map[string]bool // The HOBs that were requested
+	err  error
+}
+
+// loadWaiters broadcasts the completion of multiconfig loads, and coalesces the waits of concurrent readers of the
+// same HOB
+type loadWaiters struct {
+	mtx     sync.Mutex
+	done    chan struct{} // Closed (and replaced) when a load completes
+	last    loadResult
+	loaded  time.Time // When the HOBs were last loaded successfully
+	flights map[string]*hobFlight
+}
+
+// hobFlight is a wait for a HOB shared by concurrent readers
+type hobFlight struct {
+	done chan struct{}
+	hob  *Hob
+	err  error
+}
+
+// loadComplete wakes everything waiting for a load, which (if successful) loaded the HOBs as they were at the time
+func (w *loadWaiters) loadComplete(hobs []string, at time.Time, err error) {
+	requested := make(map[string]bool, len(hobs))
+	for _, hob := range hobs {
+		requested[hob] = true
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.last = loadResult{hobs: requested, err: err}
+	if err == nil && at.After(w.loaded) {
+		w.loaded = at
+	}
+	if w.done != nil {
+		close(w.done)
+		w.done = nil
+	}
+}
+
+// nextLoad returns a channel closed when the next load completes
+func (w *loadWaiters) nextLoad() <-chan struct{} {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.done == nil {
+		w.done = make(chan struct{})
+	}
+	return w.done
+}
+
+func (w *loadWaiters) lastLoad() loadResult {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.last
+}
+
+// lastLoaded returns when the HOBs were last loaded successfully, or the zero time if they haven't been
+func (w *loadWaiters) lastLoaded() time.Time {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.loaded
+}
+
+// ReadHobContext returns the HOB from the cache, waiting for it to be loaded if it isn't yet cached. It returns an
+// error if the hob service doesn't know the HOB, or the context is done first.
+func (c *cache) ReadHobContext(ctx context.Context, hob string) (*Hob, error) {
+	if h := c.peekHob(hob); h != nil {
+		return h, nil
+	}
+
+	for {
+		c.loadWaiters.mtx.Lock()
+		if c.flights == nil {
+			c.flights = make(map[string]*hobFlight)
+		}
+		flight, waiting := c.flights[hob]
+		if !waiting {
+			flight = &hobFlight{done: make(chan struct{})}
+			c.flights[hob] = flight
+		}
+		c.loadWaiters.mtx.Unlock()
+
+		if !waiting {
+			flight.hob, flight.err = c.waitForHob(ctx, hob)
+			c.loadWaiters.mtx.Lock()
+			delete(c.flights, hob)
+			c.loadWaiters.mtx.Unlock()
+			close(flight.done)
+			return flight.hob, flight.err
+		}
+
+		select {
+		case <-flight.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// If the wait we shared ended because its own context was done, and ours isn't, wait again
+		if flight.err == context.Canceled || flight.err == context.DeadlineExceeded {
+			continue
+		}
+		return flight.hob, flight.err
+	}
+}
+
+func (c *cache) waitForHob(ctx context.Context, hob string) (*Hob, error) {
+	// Only the first read schedules a load
+	read := c.ReadHob
+	for {
+		next := c.nextLoad()
+		if h := read(hob); h != nil {
+			return h, nil
+		}
+		read = c.peekHob
+
+		select {
+		case <-next:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if h := c.peekHob(hob); h != nil {
+			return h, nil
+		}
+		if result := c.lastLoad(); result.hobs[hob] && result.err == nil {
+			return nil, fmt.Errorf("Could not find hob \"%s\"", hob)
+		}
+		// The load failed (it'll be retried), or didn't include the HOB
+	}
+}
+
+// peekHob returns the HOB if it's cached, without scheduling a load if it isn't
+func (c *cache) peekHob(hob string) *Hob {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	if hData := c.hobs[hob]; hData != nil {
+		return hData.Hob
+	}
+	return nil
+}
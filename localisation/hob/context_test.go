@@ -0,0 +1,80 @@
+package hob
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// countingConfigService counts calls to Multiconfig
+type countingConfigService struct {
+	mockConfigService
+	calls int32
+}
+
+func (s *countingConfigService) Multiconfig(hobs, hobHashes, serviceTypesHashes []string) (newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes []string, err error) {
+	atomic.AddInt32(&s.calls, 1)
+	return s.mockConfigService.Multiconfig(hobs, hobHashes, serviceTypesHashes)
+}
+
+func TestReadHobContext(t *testing.T) {
+	service := &countingConfigService{}
+	c := MemoryHobsCache(CacheHobService(service)).(*cache)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	hobs := make([]*Hob, 5)
+	for i := range hobs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			h, err := c.ReadHobContext(ctx, "MCK")
+			assert.NoError(t, err)
+			hobs[i] = h
+		}(i)
+	}
+	wg.Wait()
+
+	for _, h := range hobs {
+		if assert.NotNil(t, h) {
+			assert.Equal(t, "MCK", h.Code)
+		}
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&service.calls))
+
+	// Now cached
+	h, err := c.ReadHobContext(ctx, "MCK")
+	assert.NoError(t, err)
+	assert.Equal(t, hobs[0], h)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&service.calls))
+}
+
+func TestReadHobContextNotFound(t *testing.T) {
+	c := MemoryHobsCache(CacheHobService(&mockConfigService{})).(*cache)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	h, err := c.ReadHobContext(ctx, "XXX")
+	assert.Nil(t, h)
+	assert.EqualError(t, err, `Could not find hob "XXX"`)
+}
+
+func TestReadHobContextDeadline(t *testing.T) {
+	c := MemoryHobsCache(CacheHobService(&mockConfigService{})).(*cache)
+	defer c.Close()
+
+	// The mock service takes 100ms to load
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	h, err := c.ReadHobContext(ctx, "MCK")
+	assert.Nil(t, h)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
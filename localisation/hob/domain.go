@@ -1,6 +1,8 @@
 package hob
 
 import (
+	log "github.com/cihub/seelog"
+
 	"github.com/HailoOSS/platform/server"
 
 	"fmt"
@@ -35,7 +37,14 @@ func GetHob(hob string) (*Hob, error) {
 		return cachedHob, nil
 	}
 
-	return h2HobService.ReadHob(hob)
+	h, err := cacheHobService().ReadHob(hob)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.Validate(); err != nil {
+		log.Errorf("invalid config for hob:%v err:%v", hob, err)
+	}
+	return h, nil
 }
 
 // Deprecated, use GetServiceType(hob, serviceType) instead
@@ -68,7 +77,20 @@ func GetServiceTypes(hob string) (ServiceTypes, error) {
 		return cachedServiceTypes, nil
 	}
 
-	return h2HobService.ReadServiceTypes(hob)
+	return cacheHobService().ReadServiceTypes(hob)
+}
+
+// serviceHobsCache is implemented by caches which load HOBs from a particular HobService
+type serviceHobsCache interface {
+	hobService() HobService
+}
+
+// cacheHobService returns the HobService Cache loads HOBs from, so that cache misses are read from the same one
+func cacheHobService() HobService {
+	if c, ok := Cache.(serviceHobsCache); ok {
+		return c.hobService()
+	}
+	return defaultHobService()
 }
 
 // Depricated, use GetTieredServiceTypeList instead
@@ -0,0 +1,250 @@
+package hob
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// StaticHobService is a HobService serving fixed HOBs, eg: for tests. Multiconfig behaves as the hob service's does,
+// only returning the configs of HOBs whose hashes have changed.
+type StaticHobService struct {
+	mtx  sync.RWMutex
+	hobs map[string]*HobData
+}
+
+// NewStaticHobService returns a StaticHobService serving the HOBs. Panics if any of them has no Hob.
+func NewStaticHobService(hobs ...*HobData) *StaticHobService {
+	s := &StaticHobService{
+		hobs: make(map[string]*HobData, len(hobs)),
+	}
+	for _, hobData := range hobs {
+		if err := s.Add(hobData); err != nil {
+			panic(err)
+		}
+	}
+	return s
+}
+
+// Add adds (or replaces) a HOB, by its code
+func (s *StaticHobService) Add(hobData *HobData) error {
+	if hobData == nil || hobData.Hob == nil {
+		return fmt.Errorf("Static HOB data has no hob")
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.hobs[hobData.Hob.Code] = hobData
+	return nil
+}
+
+func (s *StaticHobService) lookup(hob string) (*HobData, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.hobs[hob], nil
+}
+
+func (s *StaticHobService) ReadHob(hob string) (*Hob, error) {
+	return readHobFrom(s.lookup, hob)
+}
+
+func (s *StaticHobService) ReadServiceTypes(hob string) (ServiceTypes, error) {
+	return readServiceTypesFrom(s.lookup, hob)
+}
+
+func (s *StaticHobService) Multiconfig(hobs, hobHashes, serviceTypesHashes []string) (newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes []string, err error) {
+	return multiconfigFrom(s.lookup, hobs, hobHashes, serviceTypesHashes)
+}
+
+// FileHobService is a HobService reading HOBs from a directory, eg: for local development or CLI tools. Each HOB is
+// read from a file named by its code, either <code>.json or <code>.yaml (or .yml), holding its HobData. Files are
+// read on every call, so changes to them are picked up by the next reload.
+type FileHobService struct {
+	Dir string
+}
+
+// NewFileHobService returns a FileHobService reading HOBs from the directory
+func NewFileHobService(dir string) *FileHobService {
+	return &FileHobService{
+		Dir: dir,
+	}
+}
+
+// lookup reads a HOB's file, returning nil if there isn't one
+func (s *FileHobService) lookup(hob string) (*HobData, error) {
+	if hob == "" || strings.ContainsAny(hob, `/\.`) {
+		return nil, nil
+	}
+	for _, ext := range []string{".json", ".yaml", ".yml"} {
+		path := filepath.Join(s.Dir, hob+ext)
+		b, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if ext != ".json" {
+			if b, err = yamlToJson(b); err != nil {
+				return nil, fmt.Errorf("Error reading %s: %v", path, err)
+			}
+		}
+		hobData := &HobData{}
+		if err := json.Unmarshal(b, hobData); err != nil {
+			return nil, fmt.Errorf("Error reading %s: %v", path, err)
+		}
+		if hobData.Hob == nil {
+			return nil, fmt.Errorf("Error reading %s: no hob", path)
+		}
+		if hobData.Hob.Code != hob {
+			return nil, fmt.Errorf("Error reading %s: hob code is '%s'", path, hobData.Hob.Code)
+		}
+		return hobData, nil
+	}
+	return nil, nil
+}
+
+func (s *FileHobService) ReadHob(hob string) (*Hob, error) {
+	return readHobFrom(s.lookup, hob)
+}
+
+func (s *FileHobService) ReadServiceTypes(hob string) (ServiceTypes, error) {
+	return readServiceTypesFrom(s.lookup, hob)
+}
+
+func (s *FileHobService) Multiconfig(hobs, hobHashes, serviceTypesHashes []string) (newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes []string, err error) {
+	return multiconfigFrom(s.lookup, hobs, hobHashes, serviceTypesHashes)
+}
+
+// hobLookup returns a HOB's data, or nil if it doesn't exist
+type hobLookup func(hob string) (*HobData, error)
+
+func readHobFrom(lookup hobLookup, hob string) (*Hob, error) {
+	hobData, err := lookup(hob)
+	if err != nil {
+		return nil, err
+	}
+	if hobData == nil {
+		return nil, fmt.Errorf("Could not find hob \"%s\"", hob)
+	}
+	// Callers validate the HOB they're given, so mustn't share the stored one
+	h := *hobData.Hob
+	return &h, nil
+}
+
+func readServiceTypesFrom(lookup hobLookup, hob string) (ServiceTypes, error) {
+	hobData, err := lookup(hob)
+	if err != nil {
+		return nil, err
+	}
+	if hobData == nil {
+		return nil, fmt.Errorf("Could not find hob \"%s\"", hob)
+	}
+	// As with HOBs, callers mustn't share the stored service types, so they're copied (via JSON, as the hob service
+	// delivers them)
+	serviceTypes := make(ServiceTypes, len(hobData.ServiceTypes))
+	if len(hobData.ServiceTypes) > 0 {
+		b, err := json.Marshal(hobData.ServiceTypes)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &serviceTypes); err != nil {
+			return nil, err
+		}
+	}
+	return serviceTypes, nil
+}
+
+// multiconfigFrom returns the configs of the known HOBs, omitting those whose hashes haven't changed
+func multiconfigFrom(lookup hobLookup, hobs, hobHashes, serviceTypesHashes []string) (newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes []string, err error) {
+	if len(hobs) != len(hobHashes) {
+		hobHashes = make([]string, len(hobs))
+	}
+	if len(hobs) != len(serviceTypesHashes) {
+		serviceTypesHashes = make([]string, len(hobs))
+	}
+
+	for i, hob := range hobs {
+		hobData, err := lookup(hob)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		if hobData == nil {
+			continue
+		}
+
+		hobConfig, hobHash, err := configAndHash(hobData.Hob, hobData.HobHash)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		serviceTypes := hobData.ServiceTypes
+		if serviceTypes == nil {
+			serviceTypes = make(ServiceTypes)
+		}
+		serviceTypesConfig, serviceTypesHash, err := configAndHash(serviceTypes, hobData.ServiceTypesHash)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+
+		if hobHash == hobHashes[i] {
+			hobConfig = ""
+		}
+		if serviceTypesHash == serviceTypesHashes[i] {
+			serviceTypesConfig = ""
+		}
+		newHobs = append(newHobs, hob)
+		newHobsConfigs = append(newHobsConfigs, hobConfig)
+		newHobsHashes = append(newHobsHashes, hobHash)
+		newServiceTypesConfigs = append(newServiceTypesConfigs, serviceTypesConfig)
+		newServiceTypesHashes = append(newServiceTypesHashes, serviceTypesHash)
+	}
+	return newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes, nil
+}
+
+// configAndHash returns the JSON config of v, and its hash (the MD5 of the config unless one is given)
+func configAndHash(v interface{}, hash string) (string, string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", "", err
+	}
+	if hash == "" {
+		sum := md5.Sum(b)
+		hash = hex.EncodeToString(sum[:])
+	}
+	return string(b), hash, nil
+}
+
+// yamlToJson converts YAML to JSON, so it can be unmarshalled using the json tags
+func yamlToJson(b []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonValue(v))
+}
+
+// jsonValue converts the maps yaml unmarshals into (which have interface{} keys) into ones json can marshal
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			result[fmt.Sprint(key)] = jsonValue(value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			result[i] = jsonValue(value)
+		}
+		return result
+	}
+	return v
+}
@@ -0,0 +1,166 @@
+package hob
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	lonJson = `{
+	"hob": {"code": "LON", "name": "London", "currency": "GBP", "timezone": "Europe/London"},
+	"serviceTypes": {"black-cab": {"id": "black-cab", "name": "Black cab", "maxFare": 999}}
+}`
+	madYaml = `
+hob:
+  code: MAD
+  name: Madrid
+  currency: EUR
+  timezone: Europe/Madrid
+serviceTypes:
+  taxi:
+    id: taxi
+    name: Taxi
+    minFare: 2.5
+`
+)
+
+func fixtureDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "hobs")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "LON.json"), []byte(lonJson), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "MAD.yaml"), []byte(madYaml), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "BAD.yml"), []byte("hob:\n  code: DUB\n"), 0644))
+	return dir
+}
+
+func TestFileHobService(t *testing.T) {
+	dir := fixtureDir(t)
+	defer os.RemoveAll(dir)
+	s := NewFileHobService(dir)
+
+	h, err := s.ReadHob("LON")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "London", h.Name)
+		assert.Equal(t, "GBP", h.Currency)
+	}
+	h, err = s.ReadHob("MAD")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Madrid", h.Name)
+		assert.Equal(t, "Europe/Madrid", h.Timezone)
+	}
+	sts, err := s.ReadServiceTypes("MAD")
+	if assert.NoError(t, err) && assert.Contains(t, sts, "taxi") {
+		assert.Equal(t, 2.5, sts["taxi"].MinFare)
+	}
+
+	_, err = s.ReadHob("NYC")
+	assert.EqualError(t, err, `Could not find hob "NYC"`)
+	_, err = s.ReadHob("../LON")
+	assert.Error(t, err)
+	_, err = s.ReadHob("BAD")
+	assert.Error(t, err)
+}
+
+func TestStaticHobServiceMulticonfig(t *testing.T) {
+	s := NewStaticHobService(&HobData{
+		Hob:          &Hob{Code: "LON", Name: "London"},
+		ServiceTypes: ServiceTypes{"a": &ServiceType{Id: "a"}},
+	})
+
+	hobs, hobConfigs, hobHashes, stConfigs, stHashes, err := s.Multiconfig([]string{"LON", "NYC"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"LON"}, hobs)
+	assert.NotEmpty(t, hobConfigs[0])
+	assert.NotEmpty(t, hobHashes[0])
+	assert.NotEmpty(t, stConfigs[0])
+	assert.NotEmpty(t, stHashes[0])
+
+	// Unchanged configs aren't returned again
+	_, hobConfigs2, hobHashes2, stConfigs2, stHashes2, err := s.Multiconfig([]string{"LON"}, hobHashes, stHashes)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, hobConfigs2)
+	assert.Equal(t, []string{""}, stConfigs2)
+	assert.Equal(t, hobHashes, hobHashes2)
+	assert.Equal(t, stHashes, stHashes2)
+
+	// Only the changed config is
+	assert.NoError(t, s.Add(&HobData{
+		Hob:          &Hob{Code: "LON", Name: "London"},
+		ServiceTypes: ServiceTypes{"b": &ServiceType{Id: "b"}},
+	}))
+	_, hobConfigs2, _, stConfigs2, stHashes2, err = s.Multiconfig([]string{"LON"}, hobHashes, stHashes)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, hobConfigs2)
+	assert.NotEmpty(t, stConfigs2[0])
+	assert.NotEqual(t, stHashes, stHashes2)
+}
+
+func TestSetHobService(t *testing.T) {
+	dir := fixtureDir(t)
+	defer os.RemoveAll(dir)
+	setup()
+	SetHobService(NewFileHobService(dir))
+
+	c := MemoryHobsCache().(*cache)
+	defer c.Close()
+	assert.NoError(t, c.readMulticonfig([]string{"LON", "MAD"}, []string{"", ""}, []string{"", ""}))
+	if h := c.ReadHob("MAD"); assert.NotNil(t, h) {
+		assert.Equal(t, "EUR", h.Currency)
+	}
+	assert.Contains(t, c.ReadServiceTypes("LON"), "black-cab")
+}
+
+func TestStaticHobServiceReadHobCopies(t *testing.T) {
+	s := NewStaticHobService(&HobData{Hob: &Hob{Code: "LON", Timezone: "Europe/London"}})
+
+	// Each read is validated by its caller, so concurrent reads mustn't share a HOB
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h, err := s.ReadHob("LON")
+			if assert.NoError(t, err) {
+				assert.NoError(t, h.Validate())
+			}
+		}()
+	}
+	wg.Wait()
+
+	h1, _ := s.ReadHob("LON")
+	h2, _ := s.ReadHob("LON")
+	assert.False(t, h1 == h2, "Each read should return a copy")
+	assert.Equal(t, *h1, *h2)
+}
+
+func TestStaticHobServiceReadServiceTypesCopies(t *testing.T) {
+	s := NewStaticHobService(&HobData{
+		Hob:          &Hob{Code: "LON"},
+		ServiceTypes: ServiceTypes{"a": &ServiceType{Id: "a", MaxFare: 999}},
+	})
+
+	serviceTypes, err := s.ReadServiceTypes("LON")
+	if assert.NoError(t, err) {
+		serviceTypes["a"].MaxFare = 1
+		serviceTypes["b"] = &ServiceType{Id: "b"}
+	}
+
+	serviceTypes, err = s.ReadServiceTypes("LON")
+	if assert.NoError(t, err) && assert.Len(t, serviceTypes, 1) {
+		assert.Equal(t, 999.0, serviceTypes["a"].MaxFare, "Changes to service types read shouldn't be stored")
+	}
+}
+
+func TestStaticHobServiceAddWithoutHob(t *testing.T) {
+	s := NewStaticHobService()
+	assert.Error(t, s.Add(&HobData{}))
+	assert.Error(t, s.Add(nil))
+	assert.Panics(t, func() { NewStaticHobService(&HobData{}) })
+}
@@ -2,6 +2,7 @@ package hob
 
 import (
 	"fmt"
+	"sync"
 	log "github.com/cihub/seelog"
 	"github.com/HailoOSS/protobuf/proto"
 
@@ -17,7 +18,26 @@ type HobService interface {
 	Multiconfig(hobs, hobHashes, serviceTypesHashes []string) (newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes []string, err error)
 }
 
-var h2HobService HobService = &H2HobService{}
+var (
+	h2HobService    HobService = &H2HobService{}
+	h2HobServiceMtx sync.RWMutex
+)
+
+// SetHobService installs the HobService used to load HOBs (by default, the hob service). It should be called at
+// startup: it's safe to call at any time, but HOBs already cached aren't reloaded from the new service until they
+// change.
+func SetHobService(s HobService) {
+	h2HobServiceMtx.Lock()
+	defer h2HobServiceMtx.Unlock()
+	h2HobService = s
+}
+
+// defaultHobService returns the HobService installed by SetHobService
+func defaultHobService() HobService {
+	h2HobServiceMtx.RLock()
+	defer h2HobServiceMtx.RUnlock()
+	return h2HobService
+}
 
 type H2HobService struct{}
 
@@ -2,6 +2,7 @@ package hob
 
 import (
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -105,18 +106,74 @@ type Hob struct {
 	JobOfferVolumeOverride bool           `json:"jobOfferVolumeOverride" description:"Allow user to override job offer volume"`
 	CustomJobRingtone      bool           `json:"customJobRingtone"`
 	Fastpay                Fastpay        `json:"fastpay"`
+
+	location    *time.Location // Resolved by Validate
+	locationErr error
+}
+
+// ValidationError describes invalid configuration found when loading a HOB
+type ValidationError struct {
+	Hob   string
+	Field string
+	Value string // The invalid value, or "" if the field is missing
+	Err   error  // Why the value is invalid (nil if the field is missing)
+}
+
+func (e *ValidationError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("No %s defined for HOB '%v'", e.Field, e.Hob)
+	}
+	return fmt.Sprintf("Failed to load %s '%v' for HOB '%v': %v", e.Field, e.Value, e.Hob, e.Err)
+}
+
+// Validate checks the HOB's configuration, resolving and memoising its timezone so Location doesn't have to load it
+// again. It's called as HOBs are loaded into the cache, so should not be called on a HOB read from it.
+func (h *Hob) Validate() error {
+	h.location, h.locationErr = h.loadLocation()
+	return h.locationErr
 }
 
 // Location yields a time.Location appropriate for this HOB, or an error if failed to load
 func (h *Hob) Location() (*time.Location, error) {
+	if h.location != nil || h.locationErr != nil {
+		return h.location, h.locationErr
+	}
+	return h.loadLocation()
+}
+
+func (h *Hob) loadLocation() (*time.Location, error) {
 	tz := h.Timezone
 	if tz == "" {
-		return nil, fmt.Errorf("No timezone defined for HOB '%v'", h.Code)
+		return nil, &ValidationError{Hob: h.Code, Field: "timezone"}
+	}
+	l, err := loadLocation(tz)
+	if err != nil {
+		return nil, &ValidationError{Hob: h.Code, Field: "timezone", Value: tz, Err: err}
+	}
+	return l, nil
+}
+
+var (
+	locations    = make(map[string]*time.Location)
+	locationsMtx sync.RWMutex
+)
+
+// loadLocation memoises time.LoadLocation, which reads the zoneinfo from disk each time
+func loadLocation(tz string) (*time.Location, error) {
+	locationsMtx.RLock()
+	l, ok := locations[tz]
+	locationsMtx.RUnlock()
+	if ok {
+		return l, nil
 	}
+
 	l, err := time.LoadLocation(tz)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to load timezone '%v' for HOB '%v': %v", tz, h.Code, err)
+		return nil, err
 	}
+	locationsMtx.Lock()
+	locations[tz] = l
+	locationsMtx.Unlock()
 	return l, nil
 }
 
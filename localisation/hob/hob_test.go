@@ -0,0 +1,64 @@
+package hob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHobValidate(t *testing.T) {
+	h := &Hob{Code: "LON", Timezone: "Europe/London"}
+	assert.NoError(t, h.Validate())
+	l, err := h.Location()
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/London", l.String())
+	assert.True(t, l == h.location, "Location should be memoised")
+
+	local, err := h.LocalTime(time.Date(2015, time.July, 1, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 13, local.Hour())
+
+	h = &Hob{Code: "XXX", Timezone: "Europe/Nowhere"}
+	err = h.Validate()
+	if assert.IsType(t, &ValidationError{}, err) {
+		assert.Equal(t, "timezone", err.(*ValidationError).Field)
+		assert.Equal(t, "XXX", err.(*ValidationError).Hob)
+		assert.Contains(t, err.Error(), "Failed to load timezone 'Europe/Nowhere' for HOB 'XXX'")
+	}
+	_, locErr := h.Location()
+	assert.Equal(t, err, locErr, "Location should return the validation error")
+
+	h = &Hob{Code: "XXX"}
+	if err := h.Validate(); assert.Error(t, err) {
+		assert.Equal(t, "No timezone defined for HOB 'XXX'", err.Error())
+	}
+}
+
+func TestHobLocationWithoutValidate(t *testing.T) {
+	h := &Hob{Code: "NYC", Timezone: "America/New_York"}
+	l1, err := h.Location()
+	assert.NoError(t, err)
+	l2, _ := (&Hob{Code: "NYC", Timezone: "America/New_York"}).Location()
+	assert.True(t, l1 == l2, "Locations should be shared between HOBs")
+
+	_, err = (&Hob{Code: "XXX", Timezone: "Europe/Nowhere"}).Location()
+	assert.Error(t, err)
+}
+
+func TestCacheValidatesHobs(t *testing.T) {
+	c := MemoryHobsCache(CacheHobService(&mockConfigService{})).(*cache)
+	sub := c.Subscribe(10)
+	defer sub.Close()
+	assert.NoError(t, c.readMulticonfig([]string{"MCK"}, []string{""}, []string{""}))
+
+	h := c.ReadHob("MCK")
+	if assert.NotNil(t, h) {
+		// The mock HOB has no timezone
+		assert.IsType(t, &ValidationError{}, h.locationErr)
+		assert.Equal(t, h.locationErr, c.hobs["MCK"].ValidationErr)
+	}
+	event := <-sub.C
+	assert.Equal(t, HobAdded, event.Type)
+	assert.IsType(t, &ValidationError{}, event.Err, "The event should carry the validation error")
+}
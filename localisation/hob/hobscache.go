@@ -15,10 +15,6 @@ import (
 	"github.com/HailoOSS/go-service-layer/config"
 )
 
-var (
-	memoryCacheImpl map[string]*HobData = make(map[string]*HobData) // map[LON] to *HobData of London
-)
-
 type HobsCache interface {
 	ReadHob(hob string) *Hob
 	ReadServiceTypes(hob string) ServiceTypes
@@ -29,6 +25,7 @@ type HobData struct {
 	HobHash          string       `json:"hobHash"`
 	ServiceTypes     ServiceTypes `json:"serviceTypes"`
 	ServiceTypesHash string       `json:"serviceTypesHash"`
+	ValidationErr    error        `json:"-"` // Why the HOB's config is invalid (eg: a *ValidationError), if it is
 }
 
 type MockHobsCache struct {
@@ -47,26 +44,140 @@ func (c *MockHobsCache) ReadServiceTypes(hob string) ServiceTypes {
 	return ret.Get(0).(ServiceTypes)
 }
 
+// CacheOption configures a HobsCache
+type CacheOption func(*cache)
+
+// CacheHobService sets the HobService the cache loads HOBs from (by default, the one installed by SetHobService)
+func CacheHobService(s HobService) CacheOption {
+	return func(c *cache) {
+		c.service = s
+	}
+}
+
+// ReloadPeriod sets how often all the cached HOBs are reloaded (default 1 hour), plus up to jitter (default 1 minute).
+// Non-positive periods, and negative jitters, are ignored.
+func ReloadPeriod(period, jitter time.Duration) CacheOption {
+	return func(c *cache) {
+		if period > 0 {
+			c.reloadPeriod = period
+		}
+		if jitter >= 0 {
+			c.reloadJitter = jitter
+		}
+	}
+}
+
+// TriggerReloadJitter sets the maximum random delay before reloading when config changes (default 10 seconds).
+// Negative jitters are ignored.
+func TriggerReloadJitter(jitter time.Duration) CacheOption {
+	return func(c *cache) {
+		if jitter >= 0 {
+			c.triggerJitter = jitter
+		}
+	}
+}
+
+// RetryBackoff sets the backoff between attempts to reload, when loading fails
+func RetryBackoff(min, max time.Duration, factor float64) CacheOption {
+	return func(c *cache) {
+		c.backoff = &backoff.Backoff{
+			Min:    min,
+			Max:    max,
+			Factor: factor,
+			Jitter: true,
+		}
+	}
+}
+
 type cache struct {
 	initRefresher sync.Once
 	mtx           sync.RWMutex
+	hobs          map[string]*HobData // map[LON] to *HobData of London
+	service       HobService
 	rnd           *rand.Rand
 	scopeFrom     multiclient.Scoper
 	refresh       chan string
+	reloadPeriod  time.Duration
+	reloadJitter  time.Duration
+	triggerJitter time.Duration
 	backoff       *backoff.Backoff
+	stop          chan struct{}
+	stopped       chan struct{} // Closed when reloading stops after the cache is closed
+	closeOnce     sync.Once
+	subscribers
+	loadWaiters
 }
 
-func MemoryHobsCache() HobsCache {
-	return &cache{
-		rnd:       rand.New(rand.NewSource(time.Now().UTC().UnixNano())),
-		scopeFrom: server.Scoper(),
-		refresh:   make(chan string, 10),
+// MemoryHobsCache returns a HobsCache holding HOBs in memory, which loads them (in the background) when they're first
+// read, and reloads them periodically and when config changes
+func MemoryHobsCache(opts ...CacheOption) HobsCache {
+	c := &cache{
+		hobs:          make(map[string]*HobData),
+		rnd:           rand.New(rand.NewSource(time.Now().UTC().UnixNano())),
+		scopeFrom:     server.Scoper(),
+		refresh:       make(chan string, 10),
+		reloadPeriod:  forceReloadPeriod,
+		reloadJitter:  forceReloadJitter,
+		triggerJitter: triggerReloadJitter,
 		backoff: &backoff.Backoff{
 			Min:    failedMinLoadRetry,
 			Max:    failedMaxLoadRetry,
 			Factor: failedLoadRetryFactor,
 			Jitter: true,
 		},
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Close stops the cache reloading (and writing snapshots), and closes its subscriptions. HOBs already loaded can still
+// be read. Caches are closed through io.Closer, eg: Cache.(io.Closer).Close().
+func (c *cache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+
+		c.subscribers.mtx.Lock()
+		subs := make([]*Subscription, 0, len(c.subs))
+		for sub := range c.subs {
+			subs = append(subs, sub)
+		}
+		c.subscribers.mtx.Unlock()
+		for _, sub := range subs {
+			sub.Close()
+		}
+	})
+	return nil
+}
+
+func (c *cache) hobService() HobService {
+	if c.service != nil {
+		return c.service
+	}
+	return defaultHobService()
+}
+
+func (c *cache) closed() bool {
+	select {
+	case <-c.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleep waits for d, returning false if the cache is closed first
+func (c *cache) sleep(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-c.stop:
+		return false
 	}
 }
 
@@ -91,16 +202,16 @@ func (c *cache) ReadServiceTypes(hob string) ServiceTypes {
 func (c *cache) readHobDataFromCache(hob string) *HobData {
 	// Read the hobData from memcache
 	c.mtx.RLock()
-	readHob, ok := memoryCacheImpl[hob]
+	readHob, ok := c.hobs[hob]
 	c.mtx.RUnlock()
 	if ok && readHob != nil {
 		return readHob
 	} // else
 
 	c.mtx.Lock()
-	readHob, ok = memoryCacheImpl[hob] // double check the values after acquiring the lock
+	readHob, ok = c.hobs[hob] // double check the values after acquiring the lock
 	if !ok {
-		memoryCacheImpl[hob] = nil
+		c.hobs[hob] = nil
 	}
 	c.mtx.Unlock()
 
@@ -122,20 +233,31 @@ func (c *cache) scheduleReloadPeriodically() {
 }
 
 func (c *cache) reloadPeriodically() {
+	defer close(c.stopped)
 	ch := config.SubscribeChanges()
 	log.Infof("Scheduling reload")
-	tick := time.NewTicker(time.Duration(int64(forceReloadPeriod) + int64(c.jitter(forceReloadJitter))))
+	tick := time.NewTicker(time.Duration(int64(c.reloadPeriod) + int64(c.jitter(c.reloadJitter))))
+	defer tick.Stop()
 	for {
 		select {
+		case <-c.stop:
+			log.Infof("Stopping reload")
+			return
 		case <-tick.C:
 			log.Debugf("Config reload triggered by timer")
 		case <-ch:
 			log.Debugf("Config reload triggered by config notification")
 			// add some jitter
-			time.Sleep(c.jitter(triggerReloadJitter))
+			if !c.sleep(c.jitter(c.triggerJitter)) {
+				return
+			}
 		case hob, ok := <-c.refresh:
 			log.Debugf("Config reload triggered by query for new hob:%s ok:%v", hob, ok)
 		}
+		// select picks at random if the cache was closed as a reload was triggered
+		if c.closed() {
+			return
+		}
 		knownHobs, hobHashes, serviceTypesHashes := c.createMultiConfigParams()
 		if len(knownHobs) == 0 {
 			log.Warnf("got no hobs in knownHobs list")
@@ -147,7 +269,9 @@ func (c *cache) reloadPeriodically() {
 				if c.backoff.Attempt() > reloadMaxRetryAttempts {
 					break
 				}
-				time.Sleep(c.backoff.Duration())
+				if !c.sleep(c.backoff.Duration()) {
+					return
+				}
 				continue
 			}
 			break
@@ -159,12 +283,12 @@ func (c *cache) reloadPeriodically() {
 func (c *cache) createMultiConfigParams() ([]string, []string, []string) {
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
-	knownHobsCount := len(memoryCacheImpl)
+	knownHobsCount := len(c.hobs)
 	hobs := make([]string, knownHobsCount)
 	hobHashes := make([]string, knownHobsCount)
 	serviceTypesHashes := make([]string, knownHobsCount)
 	i := 0
-	for hob, hobData := range memoryCacheImpl {
+	for hob, hobData := range c.hobs {
 		hobHash := ""
 		serviceTypesHash := ""
 		if hobData != nil {
@@ -182,19 +306,25 @@ func (c *cache) createMultiConfigParams() ([]string, []string, []string) {
 func (c *cache) jitter(j time.Duration) time.Duration {
 	return time.Duration(int64(c.rnd.Float64() * float64(j)))
 }
-func (c *cache) readMulticonfig(hobs, hobHashes, serviceTypesHashes []string) error {
+func (c *cache) readMulticonfig(hobs, hobHashes, serviceTypesHashes []string) (err error) {
+	defer func() { c.loadComplete(hobs, time.Now(), err) }()
 	log.Debugf("running hobservice.multiconfig with hobs:%v, hobHashes:%v, serviceTypeHashes:%v", hobs, hobHashes, serviceTypesHashes)
-	newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes, err := h2HobService.Multiconfig(hobs, hobHashes, serviceTypesHashes)
+	newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes, err := c.hobService().Multiconfig(hobs, hobHashes, serviceTypesHashes)
 	if err != nil {
 		return err
 	}
+	// Subscribers are notified of whatever was loaded once the lock is released (even if a later HOB fails)
+	events := make([]ChangeEvent, 0)
+	defer func() { c.publish(events) }()
+
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 	for i, hob := range newHobs {
-		hData := memoryCacheImpl[hob]
+		hData := c.hobs[hob]
 		if hData == nil {
 			hData = &HobData{}
 		}
+		oldHob, oldServiceTypes := hData.Hob, hData.ServiceTypes
 
 		if len(newHobsConfigs[i]) > 0 {
 			h := &Hob{}
@@ -203,6 +333,10 @@ func (c *cache) readMulticonfig(hobs, hobHashes, serviceTypesHashes []string) er
 				log.Errorf("error unmarshalling hob:%v err:%v", hob, err)
 				return err
 			}
+			hData.ValidationErr = h.Validate()
+			if hData.ValidationErr != nil {
+				log.Errorf("invalid config for hob:%v err:%v", hob, hData.ValidationErr)
+			}
 			hData.Hob = h
 			hData.HobHash = newHobsHashes[i]
 		}
@@ -217,7 +351,9 @@ func (c *cache) readMulticonfig(hobs, hobHashes, serviceTypesHashes []string) er
 			hData.ServiceTypes = serviceTypes
 			hData.ServiceTypesHash = newServiceTypesHashes[i]
 		}
-		memoryCacheImpl[hob] = hData
+		c.hobs[hob] = hData
+		events = append(events, withValidationErr(hobChanges(hob, oldHob, hData.Hob, oldServiceTypes,
+			hData.ServiceTypes, len(newServiceTypesConfigs[i]) > 0), hData.ValidationErr)...)
 	}
 	return nil
 }
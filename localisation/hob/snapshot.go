@@ -0,0 +1,148 @@
+package hob
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/cihub/seelog"
+)
+
+// SnapshotHobsCache is implemented by caches which can be saved to, and restored from, a file
+type SnapshotHobsCache interface {
+	LoadSnapshot(path string, maxAge time.Duration) error
+	WriteSnapshot(path string) error
+	SnapshotPeriodically(path string, period, maxAge time.Duration) error
+}
+
+// EnableSnapshots restores Cache from the snapshot file (if it's no older than maxAge), and then writes it to the file
+// every period. This saves a restarted service reloading every HOB from the hob service; only those which have
+// changed since the snapshot was written are reloaded.
+func EnableSnapshots(path string, period, maxAge time.Duration) error {
+	c, ok := Cache.(SnapshotHobsCache)
+	if !ok {
+		return fmt.Errorf("HOB cache %T doesn't support snapshots", Cache)
+	}
+	return c.SnapshotPeriodically(path, period, maxAge)
+}
+
+// snapshot is the format of a snapshot file
+type snapshot struct {
+	Timestamp time.Time           `json:"timestamp"` // When the HOBs were last loaded successfully
+	Hobs      map[string]*HobData `json:"hobs"`
+}
+
+// WriteSnapshot writes the loaded HOBs (with their hashes) to the file, replacing it atomically. The snapshot is
+// timestamped with the last successful load, so its age (as checked by LoadSnapshot) is that of the HOBs in it.
+func (c *cache) WriteSnapshot(path string) error {
+	c.mtx.RLock()
+	s := snapshot{
+		Timestamp: c.lastLoaded(),
+		Hobs:      make(map[string]*HobData, len(c.hobs)),
+	}
+	for hob, hobData := range c.hobs {
+		if hobData != nil && hobData.Hob != nil {
+			s.Hobs[hob] = hobData
+		}
+	}
+	b, err := json.Marshal(s)
+	c.mtx.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// LoadSnapshot restores the HOBs in the snapshot file (unless it's older than maxAge), other than any already loaded,
+// waking anything waiting for them, and schedules a reload so any which have since changed are refreshed
+func (c *cache) LoadSnapshot(path string, maxAge time.Duration) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	s := snapshot{}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("Invalid HOB cache snapshot %s: %v", path, err)
+	}
+	if age := time.Since(s.Timestamp); age > maxAge {
+		return fmt.Errorf("HOB cache snapshot %s is too old (%v)", path, age)
+	}
+
+	events := make([]ChangeEvent, 0)
+	restored := make([]string, 0, len(s.Hobs))
+	c.mtx.Lock()
+	for hob, hobData := range s.Hobs {
+		if hobData == nil || hobData.Hob == nil || c.hobs[hob] != nil {
+			continue
+		}
+		restored = append(restored, hob)
+		hobData.ValidationErr = hobData.Hob.Validate()
+		if hobData.ValidationErr != nil {
+			log.Errorf("invalid config for hob:%v err:%v", hob, hobData.ValidationErr)
+		}
+		c.hobs[hob] = hobData
+		events = append(events, withValidationErr(hobChanges(hob, nil, hobData.Hob, nil, hobData.ServiceTypes, true),
+			hobData.ValidationErr)...)
+	}
+	c.mtx.Unlock()
+	c.publish(events)
+	c.loadComplete(restored, s.Timestamp, nil)
+	log.Infof("Loaded %d hobs from snapshot %s, as loaded at %v", len(s.Hobs), path, s.Timestamp)
+
+	c.initRefresher.Do(c.scheduleReloadPeriodically)
+	select {
+	case c.refresh <- "":
+	default:
+	}
+	return nil
+}
+
+// SnapshotPeriodically loads the snapshot file (if it exists and is no older than maxAge), and then writes it every
+// period until the cache is closed
+func (c *cache) SnapshotPeriodically(path string, period, maxAge time.Duration) error {
+	if period <= 0 {
+		return fmt.Errorf("Invalid HOB cache snapshot period %v", period)
+	}
+	if err := c.LoadSnapshot(path, maxAge); err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("Not restoring HOB cache snapshot: %v", err)
+		}
+	}
+
+	go func() {
+		tick := time.NewTicker(period)
+		defer tick.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-tick.C:
+			}
+			if err := c.WriteSnapshot(path); err != nil {
+				log.Errorf("error writing HOB cache snapshot %s: %v", path, err)
+			}
+		}
+	}()
+	return nil
+}
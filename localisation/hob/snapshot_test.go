@@ -0,0 +1,130 @@
+package hob
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hobs")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "hobs.json")
+
+	c := MemoryHobsCache(CacheHobService(NewStaticHobService(&HobData{
+		Hob:          &Hob{Code: "LON", Name: "London", Timezone: "Europe/London"},
+		ServiceTypes: ServiceTypes{"a": &ServiceType{Id: "a", Name: "A"}},
+	}))).(*cache)
+	assert.NoError(t, c.readMulticonfig([]string{"LON"}, []string{""}, []string{""}))
+	c.hobs["NYC"] = nil // Not yet loaded, so not written
+	loaded := c.lastLoaded()
+	assert.NoError(t, c.WriteSnapshot(path))
+	hobHash := c.hobs["LON"].HobHash
+
+	// Restore into an empty cache, whose hob service holds the reload it schedules until released
+	service := &blockingHobService{HobService: &mockConfigService{}, release: make(chan struct{})}
+	c = MemoryHobsCache(CacheHobService(service)).(*cache)
+	defer c.Close()
+	sub := c.Subscribe(10)
+	defer sub.Close()
+	restored := c.nextLoad()
+	assert.NoError(t, c.LoadSnapshot(path, time.Minute))
+	<-restored
+	assert.True(t, loaded.Equal(c.lastLoaded()), "The snapshot should be timestamped with the last load")
+
+	reloaded := c.nextLoad()
+	close(service.release)
+	<-reloaded
+
+	if hobData := c.hobs["LON"]; assert.NotNil(t, hobData) {
+		assert.Equal(t, "London", hobData.Hob.Name)
+		assert.Equal(t, hobHash, hobData.HobHash)
+		assert.Contains(t, hobData.ServiceTypes, "a")
+		loc, err := hobData.Hob.Location()
+		assert.NoError(t, err)
+		assert.Equal(t, "Europe/London", loc.String())
+	}
+	assert.NotContains(t, c.hobs, "NYC")
+	assert.Equal(t, HobAdded, (<-sub.C).Type)
+}
+
+// blockingHobService holds Multiconfig calls until released
+type blockingHobService struct {
+	HobService
+	release chan struct{}
+}
+
+func (s *blockingHobService) Multiconfig(hobs, hobHashes, serviceTypesHashes []string) (newHobs, newHobsConfigs, newHobsHashes, newServiceTypesConfigs, newServiceTypesHashes []string, err error) {
+	<-s.release
+	return s.HobService.Multiconfig(hobs, hobHashes, serviceTypesHashes)
+}
+
+func TestSnapshotWakesWaiters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hobs")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "hobs.json")
+
+	b, _ := json.Marshal(snapshot{
+		Timestamp: time.Now(),
+		Hobs: map[string]*HobData{
+			"LON": {Hob: &Hob{Code: "LON", Timezone: "Europe/London"}},
+		},
+	})
+	assert.NoError(t, ioutil.WriteFile(path, b, 0644))
+
+	service := &blockingHobService{HobService: &mockConfigService{}, release: make(chan struct{})}
+	defer close(service.release)
+	c := MemoryHobsCache(CacheHobService(service)).(*cache)
+	defer c.Close()
+
+	// Wait for LON, which the blocked hob service can't load, so is only found once the snapshot is restored
+	result := make(chan *Hob, 1)
+	go func() {
+		h, _ := c.ReadHobContext(context.Background(), "LON")
+		result <- h
+	}()
+	assert.NoError(t, c.LoadSnapshot(path, time.Minute))
+	select {
+	case h := <-result:
+		if assert.NotNil(t, h) {
+			assert.Equal(t, "LON", h.Code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the restored HOB")
+	}
+}
+
+func TestSnapshotTooOld(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hobs")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "hobs.json")
+
+	b, _ := json.Marshal(snapshot{
+		Timestamp: time.Now().Add(-2 * time.Hour),
+		Hobs: map[string]*HobData{
+			"LON": {Hob: &Hob{Code: "LON"}},
+		},
+	})
+	assert.NoError(t, ioutil.WriteFile(path, b, 0644))
+
+	c := MemoryHobsCache(CacheHobService(&mockConfigService{})).(*cache)
+	assert.Error(t, c.LoadSnapshot(path, time.Hour))
+	assert.Empty(t, c.hobs)
+
+	assert.True(t, os.IsNotExist(c.LoadSnapshot(filepath.Join(dir, "missing.json"), time.Hour)))
+}
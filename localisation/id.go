@@ -0,0 +1,195 @@
+package localisation
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HailoOSS/go-hailo-lib/squish"
+)
+
+const (
+	idNodeBits    = 14
+	idCounterBits = 8
+	idTimeShift   = idNodeBits + idCounterBits
+	idCounterMask = 1<<idCounterBits - 1
+	idNodeMask    = 1<<idNodeBits - 1
+)
+
+var (
+	// idEpoch is the zero time of the timestamp portion of an ID's sequence
+	idEpoch = time.Date(2014, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// DecimalIDScheme formats IDs as the HOB followed by a base-10 sequence, eg: LON123456789
+	DecimalIDScheme IDScheme = &numericIDScheme{base: 10, format: formatDecimal, parse: parseDecimal}
+	// Base36IDScheme formats IDs as the HOB followed by a base-36 sequence, eg: LON21i3v9 (for services that need
+	// short IDs, compatible with squish.CompressTail36)
+	Base36IDScheme IDScheme = &numericIDScheme{base: 36, format: squish.Format36, parse: squish.Parse36}
+	// DefaultIDScheme is used by ID.String, ParseID and ValidateID
+	DefaultIDScheme = DecimalIDScheme
+
+	defaultIDGenerator = newIDGenerator()
+)
+
+// ID is a HOB-prefixed identifier. The sequence is time-ordered: it holds the milliseconds since 2014 followed by a
+// node identifier and a counter, so IDs generated concurrently by different processes won't collide. The node is
+// derived from the host name and process ID (see SetIDNode to assign them explicitly), and the counter allows 256 IDs
+// per millisecond, after which the sequence borrows from the following milliseconds.
+type ID struct {
+	Hob      string
+	Sequence uint64
+}
+
+// IDScheme converts IDs to and from their string representation
+type IDScheme interface {
+	Format(id ID) string
+	Parse(s string) (ID, error)
+}
+
+// NewID generates a new ID for the given HOB
+func NewID(hob string) (ID, error) {
+	if h, tail := splitID(hob); h == "" || tail != "" {
+		return ID{}, fmt.Errorf("Invalid HOB '%s' for ID", hob)
+	}
+	return ID{
+		Hob:      hob,
+		Sequence: defaultIDGenerator.next(),
+	}, nil
+}
+
+// SetIDNode sets the node identifier (up to 2^14 - 1) included in the IDs this process generates. Services which can
+// assign each of their instances a unique number should do so, as nodes derived from the host and process may (rarely)
+// collide.
+func SetIDNode(node uint64) error {
+	if node > idNodeMask {
+		return fmt.Errorf("ID node %d is more than %d", node, idNodeMask)
+	}
+	defaultIDGenerator.Lock()
+	defer defaultIDGenerator.Unlock()
+	defaultIDGenerator.node = node
+	return nil
+}
+
+// ParseID parses an ID using the DefaultIDScheme
+func ParseID(s string) (ID, error) {
+	return DefaultIDScheme.Parse(s)
+}
+
+// ValidateID checks the given string is a valid ID according to the DefaultIDScheme
+func ValidateID(s string) error {
+	_, err := ParseID(s)
+	return err
+}
+
+// String formats the ID using the DefaultIDScheme
+func (id ID) String() string {
+	return DefaultIDScheme.Format(id)
+}
+
+// Compressed formats the ID using the Base36IDScheme
+func (id ID) Compressed() string {
+	return Base36IDScheme.Format(id)
+}
+
+// Time returns when the ID was generated (to the millisecond). This is only meaningful for IDs created by NewID.
+func (id ID) Time() time.Time {
+	ms := int64(id.Sequence >> idTimeShift)
+	return idEpoch.Add(time.Duration(ms) * time.Millisecond)
+}
+
+// numericIDScheme formats the sequence as a number in some base
+type numericIDScheme struct {
+	base   int
+	format func(n uint64) string
+	parse  func(s string) (uint64, error)
+}
+
+func formatDecimal(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
+
+func parseDecimal(s string) (uint64, error) {
+	return strconv.ParseUint(s, 10, 64)
+}
+
+func (s *numericIDScheme) Format(id ID) string {
+	return id.Hob + s.format(id.Sequence)
+}
+
+func (s *numericIDScheme) Parse(str string) (ID, error) {
+	if len(str) <= 3 {
+		return ID{}, fmt.Errorf("ID '%s' is too short", str)
+	}
+	hob, tail := splitID(str)
+	if hob == "" {
+		return ID{}, fmt.Errorf("ID '%s' does not start with a HOB", str)
+	}
+	seq, err := s.parse(tail)
+	if err != nil {
+		return ID{}, fmt.Errorf("ID '%s' does not have a valid base-%d sequence: %v", str, s.base, err)
+	}
+	return ID{
+		Hob:      hob,
+		Sequence: seq,
+	}, nil
+}
+
+// idGenerator produces unique, increasing sequences for this process
+type idGenerator struct {
+	sync.Mutex
+	node    uint64
+	lastMs  uint64
+	counter uint64
+	now     func() time.Time
+}
+
+func newIDGenerator() *idGenerator {
+	host, _ := os.Hostname()
+	return &idGenerator{
+		node: idNode(host, os.Getpid()),
+		now:  time.Now,
+	}
+}
+
+// idNode derives a node identifier from a host name and process ID, by folding their hash into idNodeBits
+func idNode(host string, pid int) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%d", host, pid)
+	sum := h.Sum64()
+	node := uint64(0)
+	for ; sum != 0; sum >>= idNodeBits {
+		node ^= sum & idNodeMask
+	}
+	return node
+}
+
+func (g *idGenerator) next() uint64 {
+	g.Lock()
+	defer g.Unlock()
+
+	ms := g.millis()
+	if ms < g.lastMs {
+		// The clock went backwards; carry on from where we were rather than risk duplicates
+		ms = g.lastMs
+	}
+
+	if ms == g.lastMs {
+		g.counter = (g.counter + 1) & idCounterMask
+		if g.counter == 0 {
+			// Used up this millisecond; borrow the next one
+			ms++
+		}
+	} else {
+		g.counter = 0
+	}
+	g.lastMs = ms
+
+	return ms<<idTimeShift | g.node<<idCounterBits | g.counter
+}
+
+func (g *idGenerator) millis() uint64 {
+	return uint64(g.now().Sub(idEpoch) / time.Millisecond)
+}
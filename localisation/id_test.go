@@ -0,0 +1,140 @@
+package localisation
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/HailoOSS/go-hailo-lib/squish"
+)
+
+func TestNewID(t *testing.T) {
+	before := time.Now().Add(-time.Millisecond)
+	id, err := NewID("LON")
+	assert.NoError(t, err)
+	assert.Equal(t, "LON", id.Hob)
+	assert.Equal(t, "LON", ExtractHobFromID(id.String()))
+	assert.WithinDuration(t, before, id.Time(), time.Second)
+
+	for _, hob := range []string{"", "LO", "LOND", "lon", "L0N"} {
+		_, err := NewID(hob)
+		assert.Error(t, err, "Expected an error for HOB '%s'", hob)
+	}
+}
+
+func TestNewIDUniqueAndOrdered(t *testing.T) {
+	const n = 10000
+
+	var last uint64
+	for i := 0; i < n; i++ {
+		id, _ := NewID("LON")
+		if id.Sequence <= last {
+			t.Fatalf("IDs not increasing: %d after %d", id.Sequence, last)
+		}
+		last = id.Sequence
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mtx  sync.Mutex
+		seen = make(map[uint64]bool, n)
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n/10; j++ {
+				id, _ := NewID("MAN")
+				mtx.Lock()
+				if seen[id.Sequence] {
+					t.Errorf("Duplicate ID generated: %s", id)
+				}
+				seen[id.Sequence] = true
+				mtx.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestIDGeneratorClockSkew(t *testing.T) {
+	now := time.Date(2015, time.March, 1, 12, 0, 0, 0, time.UTC)
+	g := newIDGenerator()
+	g.now = func() time.Time { return now }
+
+	first := g.next()
+	now = now.Add(-time.Minute)
+	assert.True(t, g.next() > first, "Sequence went backwards with the clock")
+
+	// Exhaust the counter within one millisecond
+	last := g.next()
+	for i := 0; i < idCounterMask+10; i++ {
+		next := g.next()
+		assert.True(t, next > last, "Sequence not increasing after counter overflow")
+		last = next
+	}
+}
+
+func TestParseID(t *testing.T) {
+	testCases := []struct {
+		scheme IDScheme
+		in     string
+		id     ID
+		valid  bool
+	}{
+		{DecimalIDScheme, "LON1234", ID{"LON", 1234}, true},
+		{DecimalIDScheme, "DUB18446744073709551615", ID{"DUB", 18446744073709551615}, true},
+		{DecimalIDScheme, "LON", ID{}, false},
+		{DecimalIDScheme, "lon1234", ID{}, false},
+		{DecimalIDScheme, "LONDON123", ID{}, false},
+		{DecimalIDScheme, "LON12a4", ID{}, false},
+		{DecimalIDScheme, "DUB18446744073709551616", ID{}, false},
+		{Base36IDScheme, "LONya", ID{"LON", 1234}, true},
+		{Base36IDScheme, "LONYA", ID{"LON", 1234}, true},
+		{Base36IDScheme, "LONy-a", ID{}, false},
+	}
+
+	for _, tc := range testCases {
+		id, err := tc.scheme.Parse(tc.in)
+		if tc.valid {
+			assert.NoError(t, err, "Expected '%s' to be valid", tc.in)
+			assert.Equal(t, tc.id, id)
+		} else {
+			assert.Error(t, err, "Expected '%s' to be invalid", tc.in)
+		}
+	}
+
+	assert.NoError(t, ValidateID("LON1234"))
+	assert.Error(t, ValidateID("1234"))
+}
+
+func TestIDFormatting(t *testing.T) {
+	id, _ := NewID("NYC")
+
+	parsed, err := ParseID(id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	parsed, err = Base36IDScheme.Parse(id.Compressed())
+	assert.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	// Interchangeable with squish's tail compression
+	assert.Equal(t, squish.CompressTail36(3, id.String()), id.Compressed())
+	assert.Equal(t, id.String(), squish.UncompressTail36(3, id.Compressed()))
+}
+
+func TestIDNode(t *testing.T) {
+	assert.Equal(t, idNode("host-1", 1234), idNode("host-1", 1234))
+	assert.NotEqual(t, idNode("host-1", 1234), idNode("host-1", 1235))
+	assert.NotEqual(t, idNode("host-1", 1234), idNode("host-2", 1234))
+	assert.True(t, idNode("host-1", 1234) <= idNodeMask)
+
+	// Explicit nodes are included in generated IDs
+	assert.Error(t, SetIDNode(idNodeMask+1))
+	assert.NoError(t, SetIDNode(12345))
+	id, _ := NewID("LON")
+	assert.Equal(t, uint64(12345), id.Sequence>>idCounterBits&idNodeMask)
+}
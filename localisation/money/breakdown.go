@@ -0,0 +1,240 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// LineItemType identifies what a line of a Breakdown is for
+type LineItemType string
+
+const (
+	LineMeterFare  LineItemType = "METER_FARE"
+	LineTolls      LineItemType = "TOLLS"
+	LineExtras     LineItemType = "EXTRAS"
+	LineTip        LineItemType = "TIP"
+	LineFastPayFee LineItemType = "FASTPAY_FEE"
+	LineDiscount   LineItemType = "DISCOUNT"
+)
+
+// TaxMode determines whether line item amounts include tax or have it added
+type TaxMode int
+
+const (
+	// TaxInclusive means line amounts already include tax (eg: UK VAT on a metered fare)
+	TaxInclusive TaxMode = iota
+	// TaxExclusive means tax is added to line amounts (eg: US sales tax)
+	TaxExclusive
+)
+
+// LineItem is a single line of a Breakdown. Net, Tax and Gross are filled in by Calculate.
+type LineItem struct {
+	Type        LineItemType
+	Description string
+	Amount      Money // As added, ie: gross if TaxInclusive, net if TaxExclusive
+	TaxRate     int64 // In basis points, eg: 2000 for 20%
+
+	Net   Money
+	Tax   Money
+	Gross Money
+}
+
+// TaxTotal is the total tax charged at one rate, as shown on a VAT receipt
+type TaxTotal struct {
+	TaxRate int64
+	Net     Money
+	Tax     Money
+}
+
+// Breakdown itemises an amount (eg: the fare, tolls, tip and fees of a receipt) and the tax on it
+type Breakdown struct {
+	Currency string
+	Mode     TaxMode
+	Rounding RoundingMode
+	Lines    []*LineItem
+
+	Net       Money
+	Tax       Money
+	Total     Money
+	TaxTotals []TaxTotal // Ordered by rate
+}
+
+// NewBreakdown returns an empty breakdown in the currency
+func NewBreakdown(currency string, mode TaxMode, rounding RoundingMode) *Breakdown {
+	return &Breakdown{
+		Currency: strings.ToUpper(currency),
+		Mode:     mode,
+		Rounding: rounding,
+		Lines:    make([]*LineItem, 0),
+		Net:      New(0, currency),
+		Tax:      New(0, currency),
+		Total:    New(0, currency),
+	}
+}
+
+// Add adds a line to the breakdown, taxed at taxRate basis points, and recalculates the totals
+func (b *Breakdown) Add(lineType LineItemType, description string, amount Money, taxRate int64) error {
+	if amount.Currency != b.Currency {
+		return CurrencyMismatchError{Expected: b.Currency, Actual: amount.Currency}
+	}
+	if taxRate < 0 {
+		return fmt.Errorf("Invalid tax rate %d for %s", taxRate, lineType)
+	}
+	b.Lines = append(b.Lines, &LineItem{
+		Type:        lineType,
+		Description: description,
+		Amount:      amount,
+		TaxRate:     taxRate,
+	})
+	return b.Calculate()
+}
+
+// Calculate works out the tax on each line, and the totals. Each line's tax is rounded individually, then adjusted by
+// a minor unit where needed so the lines at each rate add up to the tax on their total (the lines rounded furthest
+// being adjusted first).
+func (b *Breakdown) Calculate() error {
+	byRate := make(map[int64][]*LineItem)
+	for _, line := range b.Lines {
+		byRate[line.TaxRate] = append(byRate[line.TaxRate], line)
+	}
+	rates := make([]int64, 0, len(byRate))
+	for rate := range byRate {
+		rates = append(rates, rate)
+	}
+	sort.Sort(int64s(rates))
+
+	b.Net, b.Tax, b.Total = New(0, b.Currency), New(0, b.Currency), New(0, b.Currency)
+	b.TaxTotals = make([]TaxTotal, 0, len(rates))
+	for _, rate := range rates {
+		total, err := b.calculateRate(rate, byRate[rate])
+		if err != nil {
+			return err
+		}
+		b.TaxTotals = append(b.TaxTotals, total)
+	}
+
+	for _, line := range b.Lines {
+		var err error
+		if b.Net, err = b.Net.Add(line.Net); err != nil {
+			return err
+		}
+		if b.Tax, err = b.Tax.Add(line.Tax); err != nil {
+			return err
+		}
+		if b.Total, err = b.Total.Add(line.Gross); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// calculateRate calculates the lines taxed at one rate
+func (b *Breakdown) calculateRate(rate int64, lines []*LineItem) (TaxTotal, error) {
+	// The proportion of a line's amount which is tax
+	factor := big.NewRat(rate, 10000)
+	if b.Mode == TaxInclusive {
+		factor = big.NewRat(rate, 10000+rate)
+	}
+
+	exactTotal := new(big.Rat)
+	errors := make([]*big.Rat, len(lines)) // How far each line's rounded tax is below its exact tax
+	roundedTotal := int64(0)
+	for i, line := range lines {
+		exact := new(big.Rat).Mul(new(big.Rat).SetInt64(line.Amount.Amount), factor)
+		tax, err := round(exact, b.Rounding)
+		if err != nil {
+			return TaxTotal{}, err
+		}
+		line.Tax = New(tax, b.Currency)
+		exactTotal.Add(exactTotal, exact)
+		errors[i] = exact.Sub(exact, new(big.Rat).SetInt64(tax))
+		roundedTotal += tax
+	}
+
+	// Reconcile the lines with the tax on the total
+	target, err := round(exactTotal, b.Rounding)
+	if err != nil {
+		return TaxTotal{}, err
+	}
+	for diff := target - roundedTotal; diff != 0; {
+		step := int64(1)
+		if diff < 0 {
+			step = -1
+		}
+		furthest := -1
+		for i := range lines {
+			if furthest < 0 || errors[i].Cmp(errors[furthest])*int(step) > 0 {
+				furthest = i
+			}
+		}
+		lines[furthest].Tax.Amount += step
+		errors[furthest].Sub(errors[furthest], new(big.Rat).SetInt64(step))
+		diff -= step
+	}
+
+	total := TaxTotal{TaxRate: rate, Net: New(0, b.Currency), Tax: New(0, b.Currency)}
+	for _, line := range lines {
+		if b.Mode == TaxInclusive {
+			line.Gross = line.Amount
+			line.Net = New(line.Amount.Amount-line.Tax.Amount, b.Currency)
+		} else {
+			line.Net = line.Amount
+			line.Gross = New(line.Amount.Amount+line.Tax.Amount, b.Currency)
+		}
+		total.Net.Amount += line.Net.Amount
+		total.Tax.Amount += line.Tax.Amount
+	}
+	return total, nil
+}
+
+// TemplateData returns the breakdown in a form for rendering with the templating package's filters. Amounts are in
+// minor units, for use with the currency filters, eg:
+//
+//	{% for line in breakdown.lines %}
+//	{{ line.description }}: {{ line.gross|formatCurrency:breakdown.currency }}
+//	{% endfor %}
+//	VAT: {{ breakdown.tax|formatCurrency:breakdown.currency }}
+func (b *Breakdown) TemplateData() map[string]interface{} {
+	lines := make([]map[string]interface{}, len(b.Lines))
+	for i, line := range b.Lines {
+		lines[i] = map[string]interface{}{
+			"type":        string(line.Type),
+			"description": line.Description,
+			"taxRate":     FormatRate(line.TaxRate),
+			"net":         line.Net.Amount,
+			"tax":         line.Tax.Amount,
+			"gross":       line.Gross.Amount,
+		}
+	}
+
+	taxTotals := make([]map[string]interface{}, len(b.TaxTotals))
+	for i, total := range b.TaxTotals {
+		taxTotals[i] = map[string]interface{}{
+			"taxRate": FormatRate(total.TaxRate),
+			"net":     total.Net.Amount,
+			"tax":     total.Tax.Amount,
+		}
+	}
+
+	return map[string]interface{}{
+		"currency":  b.Currency,
+		"lines":     lines,
+		"taxTotals": taxTotals,
+		"net":       b.Net.Amount,
+		"tax":       b.Tax.Amount,
+		"total":     b.Total.Amount,
+	}
+}
+
+// FormatRate formats a rate in basis points as a percentage, eg: "20%" or "8.25%"
+func FormatRate(basisPoints int64) string {
+	return strings.TrimSuffix(strings.TrimRight(big.NewRat(basisPoints, 100).FloatString(2), "0"), ".") + "%"
+}
+
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
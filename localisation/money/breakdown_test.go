@@ -0,0 +1,86 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakdownInclusive(t *testing.T) {
+	b := NewBreakdown("GBP", TaxInclusive, RoundHalfUp)
+	assert.NoError(t, b.Add(LineMeterFare, "Fare", New(1000, "GBP"), 2000))
+	assert.NoError(t, b.Add(LineExtras, "Extras", New(250, "GBP"), 2000))
+	assert.NoError(t, b.Add(LineTip, "Tip", New(200, "GBP"), 0))
+
+	// Individually the VAT rounds to 1.67 + 0.42, but on the total it's 2.08
+	assert.Equal(t, New(166, "GBP"), b.Lines[0].Tax)
+	assert.Equal(t, New(834, "GBP"), b.Lines[0].Net)
+	assert.Equal(t, New(1000, "GBP"), b.Lines[0].Gross)
+	assert.Equal(t, New(42, "GBP"), b.Lines[1].Tax)
+	assert.Equal(t, New(0, "GBP"), b.Lines[2].Tax)
+	assert.Equal(t, New(200, "GBP"), b.Lines[2].Net)
+
+	assert.Equal(t, New(1450, "GBP"), b.Total)
+	assert.Equal(t, New(208, "GBP"), b.Tax)
+	assert.Equal(t, New(1242, "GBP"), b.Net)
+	assert.Equal(t, []TaxTotal{
+		{TaxRate: 0, Net: New(200, "GBP"), Tax: New(0, "GBP")},
+		{TaxRate: 2000, Net: New(1042, "GBP"), Tax: New(208, "GBP")},
+	}, b.TaxTotals)
+}
+
+func TestBreakdownExclusive(t *testing.T) {
+	b := NewBreakdown("USD", TaxExclusive, RoundHalfEven)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, b.Add(LineTolls, "Toll", New(105, "USD"), 1000))
+	}
+	assert.NoError(t, b.Add(LineDiscount, "Promo", New(-100, "USD"), 1000))
+
+	// Each toll's tax is 10.5c, which rounds to 10c; the total 31.5c - 10c rounds to 22c
+	tax := int64(0)
+	for _, line := range b.Lines {
+		assert.Equal(t, line.Net.Amount+line.Tax.Amount, line.Gross.Amount)
+		tax += line.Tax.Amount
+	}
+	assert.Equal(t, int64(22), tax)
+	assert.Equal(t, New(22, "USD"), b.Tax)
+	assert.Equal(t, New(215, "USD"), b.Net)
+	assert.Equal(t, New(237, "USD"), b.Total)
+	assert.Equal(t, New(11, "USD"), b.Lines[0].Tax)
+	assert.Equal(t, New(-10, "USD"), b.Lines[3].Tax)
+}
+
+func TestBreakdownErrors(t *testing.T) {
+	b := NewBreakdown("GBP", TaxInclusive, RoundHalfUp)
+	assert.Error(t, b.Add(LineTip, "Tip", New(100, "EUR"), 0))
+	assert.Error(t, b.Add(LineTip, "Tip", New(100, "GBP"), -1))
+	assert.Empty(t, b.Lines)
+	assert.Equal(t, New(0, "GBP"), b.Total)
+}
+
+func TestBreakdownTemplateData(t *testing.T) {
+	b := NewBreakdown("GBP", TaxInclusive, RoundHalfUp)
+	assert.NoError(t, b.Add(LineMeterFare, "Fare", New(1200, "GBP"), 2000))
+	assert.NoError(t, b.Add(LineFastPayFee, "Fee", New(60, "GBP"), 825))
+
+	data := b.TemplateData()
+	assert.Equal(t, "GBP", data["currency"])
+	assert.Equal(t, int64(1260), data["total"])
+	assert.Equal(t, b.Tax.Amount, data["tax"])
+
+	lines := data["lines"].([]map[string]interface{})
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, "METER_FARE", lines[0]["type"])
+		assert.Equal(t, "Fare", lines[0]["description"])
+		assert.Equal(t, "20%", lines[0]["taxRate"])
+		assert.Equal(t, int64(1000), lines[0]["net"])
+		assert.Equal(t, int64(200), lines[0]["tax"])
+		assert.Equal(t, int64(1200), lines[0]["gross"])
+		assert.Equal(t, "8.25%", lines[1]["taxRate"])
+	}
+	assert.Len(t, data["taxTotals"], 2)
+
+	assert.Equal(t, "0%", FormatRate(0))
+	assert.Equal(t, "10%", FormatRate(1000))
+	assert.Equal(t, "5.5%", FormatRate(550))
+}
@@ -0,0 +1,224 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate is the exchange rate from one currency to another, in major units (ie: 1 From buys Value To)
+type Rate struct {
+	From      string
+	To        string
+	Value     *big.Rat
+	Timestamp time.Time // When the rate was published
+}
+
+// NewRate parses a decimal exchange rate, eg: NewRate("GBP", "EUR", "1.1734", t)
+func NewRate(from, to, value string, timestamp time.Time) (Rate, error) {
+	v, ok := new(big.Rat).SetString(value)
+	if !ok || v.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("Invalid exchange rate %s -> %s: '%s'", from, to, value)
+	}
+	return Rate{
+		From:      strings.ToUpper(from),
+		To:        strings.ToUpper(to),
+		Value:     v,
+		Timestamp: timestamp,
+	}, nil
+}
+
+// Inverse returns the rate in the opposite direction
+func (r Rate) Inverse() Rate {
+	return Rate{
+		From:      r.To,
+		To:        r.From,
+		Value:     new(big.Rat).Inv(r.Value),
+		Timestamp: r.Timestamp,
+	}
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%s/%s %s @ %s", r.From, r.To, r.Value.FloatString(6), r.Timestamp.Format(time.RFC3339))
+}
+
+// RateProvider supplies exchange rates. Implementations must be safe for concurrent use.
+type RateProvider interface {
+	Rate(from, to string) (Rate, error)
+}
+
+// RateProviderFunc adapts a function to a RateProvider, eg: one calling a rates service
+type RateProviderFunc func(from, to string) (Rate, error)
+
+func (f RateProviderFunc) Rate(from, to string) (Rate, error) {
+	return f(from, to)
+}
+
+// StaticRateProvider provides a fixed table of rates. If a rate is only known in one direction, its inverse is used
+// for the other.
+type StaticRateProvider struct {
+	rates map[[2]string]Rate
+}
+
+// NewStaticRateProvider returns a provider of the given rates
+func NewStaticRateProvider(rates ...Rate) *StaticRateProvider {
+	p := &StaticRateProvider{
+		rates: make(map[[2]string]Rate, len(rates)),
+	}
+	for _, rate := range rates {
+		p.rates[[2]string{rate.From, rate.To}] = rate
+	}
+	return p
+}
+
+func (p *StaticRateProvider) Rate(from, to string) (Rate, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if rate, ok := p.rates[[2]string{from, to}]; ok {
+		return rate, nil
+	}
+	if rate, ok := p.rates[[2]string{to, from}]; ok {
+		return rate.Inverse(), nil
+	}
+	return Rate{}, fmt.Errorf("No exchange rate for %s -> %s", from, to)
+}
+
+// ratesFile is the format read by LoadRatesFile, eg:
+//
+//	{"base": "GBP", "timestamp": "2015-06-01T12:00:00Z", "rates": {"EUR": "1.3912", "USD": 1.5275}}
+type ratesFile struct {
+	Base      string                 `json:"base"`
+	Timestamp time.Time              `json:"timestamp"`
+	Rates     map[string]json.Number `json:"rates"`
+}
+
+// LoadRatesFile reads a JSON file of rates from a base currency (see ratesFile), returning a provider of them (and
+// their inverses). Rates may be given as strings or numbers; strings avoid any loss of precision.
+func LoadRatesFile(path string) (*StaticRateProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening rates file: %v", err)
+	}
+	defer f.Close()
+
+	var data ratesFile
+	if err := json.NewDecoder(f).Decode(&data); err != nil {
+		return nil, fmt.Errorf("Error reading rates file %s: %v", path, err)
+	}
+	if data.Base == "" {
+		return nil, fmt.Errorf("Rates file %s has no base currency", path)
+	}
+
+	rates := make([]Rate, 0, len(data.Rates))
+	for currency, value := range data.Rates {
+		rate, err := NewRate(data.Base, currency, value.String(), data.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading rates file %s: %v", path, err)
+		}
+		rates = append(rates, rate)
+	}
+	return NewStaticRateProvider(rates...), nil
+}
+
+type cachedRate struct {
+	rate    Rate
+	fetched time.Time
+}
+
+// CachingRateProvider caches the rates returned by another provider (eg: one calling a rates service) for a period.
+// Errors aren't cached.
+type CachingRateProvider struct {
+	next RateProvider
+	ttl  time.Duration
+	now  func() time.Time
+
+	mtx   sync.RWMutex
+	cache map[[2]string]cachedRate
+}
+
+// NewCachingRateProvider returns a provider caching next's rates for ttl
+func NewCachingRateProvider(next RateProvider, ttl time.Duration) *CachingRateProvider {
+	return &CachingRateProvider{
+		next:  next,
+		ttl:   ttl,
+		now:   time.Now,
+		cache: make(map[[2]string]cachedRate),
+	}
+}
+
+func (p *CachingRateProvider) Rate(from, to string) (Rate, error) {
+	key := [2]string{strings.ToUpper(from), strings.ToUpper(to)}
+
+	p.mtx.RLock()
+	cached, ok := p.cache[key]
+	p.mtx.RUnlock()
+	if ok && p.now().Sub(cached.fetched) < p.ttl {
+		return cached.rate, nil
+	}
+
+	rate, err := p.next.Rate(key[0], key[1])
+	if err != nil {
+		return Rate{}, err
+	}
+
+	p.mtx.Lock()
+	p.cache[key] = cachedRate{rate: rate, fetched: p.now()}
+	p.mtx.Unlock()
+	return rate, nil
+}
+
+// Conversion is the result of converting an amount between currencies, along with the rate used
+type Conversion struct {
+	From Money
+	To   Money
+	Rate Rate
+}
+
+// Converter converts amounts between currencies, using the rates from a RateProvider
+type Converter struct {
+	provider RateProvider
+}
+
+// NewConverter returns a converter using the provider's rates
+func NewConverter(provider RateProvider) *Converter {
+	return &Converter{
+		provider: provider,
+	}
+}
+
+// Convert converts the amount to the currency, rounding any fraction of a minor unit with mode. Converting to the
+// amount's own currency uses a rate of 1.
+func (c *Converter) Convert(m Money, currency string, mode RoundingMode) (*Conversion, error) {
+	currency = strings.ToUpper(currency)
+
+	var rate Rate
+	if currency == m.Currency {
+		rate = Rate{From: currency, To: currency, Value: big.NewRat(1, 1), Timestamp: time.Now()}
+	} else {
+		var err error
+		if rate, err = c.provider.Rate(m.Currency, currency); err != nil {
+			return nil, err
+		}
+		if rate.From != m.Currency || rate.To != currency || rate.Value == nil || rate.Value.Sign() <= 0 {
+			return nil, fmt.Errorf("Invalid exchange rate for %s -> %s (got %s -> %s)", m.Currency, currency, rate.From,
+				rate.To)
+		}
+	}
+
+	// Scale by the difference in minor units too, eg: GBP pence -> JPY yen
+	r := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), rate.Value)
+	r.Mul(r, new(big.Rat).SetFrac(pow10(CurrencyDigits(currency)), pow10(CurrencyDigits(m.Currency))))
+	amount, err := round(r, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Conversion{
+		From: m,
+		To:   New(amount, currency),
+		Rate: rate,
+	}, nil
+}
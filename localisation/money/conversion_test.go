@@ -0,0 +1,139 @@
+package money
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRate(t *testing.T, from, to, value string) Rate {
+	rate, err := NewRate(from, to, value, time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rate
+}
+
+func TestConvert(t *testing.T) {
+	provider := NewStaticRateProvider(
+		testRate(t, "GBP", "EUR", "1.25"),
+		testRate(t, "GBP", "JPY", "190.5"),
+		testRate(t, "USD", "KWD", "0.3"),
+	)
+	converter := NewConverter(provider)
+
+	testCases := []struct {
+		from     Money
+		to       string
+		mode     RoundingMode
+		expected Money
+	}{
+		{New(1000, "GBP"), "EUR", RoundHalfUp, New(1250, "EUR")},
+		{New(1250, "EUR"), "GBP", RoundHalfUp, New(1000, "GBP")},
+		{New(2, "GBP"), "EUR", RoundHalfUp, New(3, "EUR")},
+		{New(2, "GBP"), "EUR", RoundHalfEven, New(2, "EUR")},
+		{New(1000, "GBP"), "JPY", RoundHalfUp, New(1905, "JPY")},
+		{New(1905, "JPY"), "GBP", RoundHalfUp, New(1000, "GBP")},
+		{New(1000, "USD"), "KWD", RoundHalfUp, New(3000, "KWD")},
+		{New(1000, "GBP"), "gbp", RoundHalfUp, New(1000, "GBP")},
+	}
+
+	for _, tc := range testCases {
+		conversion, err := converter.Convert(tc.from, tc.to, tc.mode)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tc.expected, conversion.To, "Converting %v to %s", tc.from, tc.to)
+			assert.Equal(t, tc.from, conversion.From)
+			assert.Equal(t, tc.expected.Currency, conversion.Rate.To)
+			assert.False(t, conversion.Rate.Timestamp.IsZero())
+		}
+	}
+
+	conversion, err := converter.Convert(New(100, "EUR"), "GBP", RoundHalfUp)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0.8", conversion.Rate.Value.FloatString(1))
+		assert.Equal(t, time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC), conversion.Rate.Timestamp)
+	}
+
+	_, err = converter.Convert(New(100, "GBP"), "USD", RoundHalfUp)
+	assert.Error(t, err)
+
+	_, err = NewRate("GBP", "EUR", "-1", time.Now())
+	assert.Error(t, err)
+	_, err = NewRate("GBP", "EUR", "abc", time.Now())
+	assert.Error(t, err)
+}
+
+func TestCachingRateProvider(t *testing.T) {
+	calls := 0
+	fail := false
+	provider := NewCachingRateProvider(RateProviderFunc(func(from, to string) (Rate, error) {
+		calls++
+		if fail {
+			return Rate{}, fmt.Errorf("Rates service unavailable")
+		}
+		return NewRate(from, to, "1.5", time.Now())
+	}), time.Minute)
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		rate, err := provider.Rate("gbp", "eur")
+		assert.NoError(t, err)
+		assert.Equal(t, "GBP", rate.From)
+	}
+	assert.Equal(t, 1, calls)
+
+	_, err := provider.Rate("GBP", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	now = now.Add(2 * time.Minute)
+	fail = true
+	_, err = provider.Rate("GBP", "EUR")
+	assert.Error(t, err)
+	_, err = provider.Rate("GBP", "EUR")
+	assert.Error(t, err)
+	assert.Equal(t, 4, calls, "Errors shouldn't be cached")
+}
+
+func TestLoadRatesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "rates.json")
+	err = ioutil.WriteFile(path, []byte(`{
+		"base": "GBP",
+		"timestamp": "2015-06-01T12:00:00Z",
+		"rates": {"EUR": "1.3912", "USD": 1.5275}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := LoadRatesFile(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	conversion, err := NewConverter(provider).Convert(New(10000, "GBP"), "USD", RoundHalfUp)
+	if assert.NoError(t, err) {
+		assert.Equal(t, New(15275, "USD"), conversion.To)
+		assert.Equal(t, time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC), conversion.Rate.Timestamp)
+	}
+	_, err = provider.Rate("EUR", "GBP")
+	assert.NoError(t, err)
+
+	_, err = LoadRatesFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	ioutil.WriteFile(path, []byte(`{"rates": {"EUR": "1.3912"}}`), 0644)
+	_, err = LoadRatesFile(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package money
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/go-hailo-lib/localisation/hob"
+)
+
+// FareVerdict is the result of checking a fare against a service type's limits
+type FareVerdict int
+
+const (
+	// FareOK means the fare is within the service type's limits
+	FareOK FareVerdict = iota
+	// FareBelowMinimum means the fare is below the lowest the service type accepts
+	FareBelowMinimum
+	// FareNeedsVerification means the fare is acceptable, but unusually low or high so should be confirmed
+	FareNeedsVerification
+	// FareAboveMaximum means the fare is above the highest the service type accepts
+	FareAboveMaximum
+)
+
+func (v FareVerdict) String() string {
+	switch v {
+	case FareOK:
+		return "OK"
+	case FareBelowMinimum:
+		return "BELOW_MINIMUM"
+	case FareNeedsVerification:
+		return "NEEDS_VERIFICATION"
+	case FareAboveMaximum:
+		return "ABOVE_MAXIMUM"
+	}
+	return fmt.Sprintf("FareVerdict(%d)", int(v))
+}
+
+// FareCheck is the verdict on a fare, along with the limit that determined it
+type FareCheck struct {
+	Fare    Money
+	Verdict FareVerdict
+	Limit   Money  // The limit the fare breached; zero if the verdict is FareOK
+	Field   string // The ServiceType field Limit came from, eg: "MaxFare"
+}
+
+func (c *FareCheck) String() string {
+	if c.Verdict == FareOK {
+		return fmt.Sprintf("%v: %s", c.Fare, c.Verdict)
+	}
+	return fmt.Sprintf("%v: %s (%s %v)", c.Fare, c.Verdict, c.Field, c.Limit)
+}
+
+// CheckFare checks a fare against the service type's limits, which are taken to be in major units of the fare's
+// currency (limits of zero are unset). In order, the fare is:
+//
+//	- FareAboveMaximum if above MaxFare
+//	- FareBelowMinimum if below MinAcceptableFare (or MinFare, if MinAcceptableFare is unset)
+//	- FareNeedsVerification if below MinUnverifiedFare, below MinFare, or above MaxUnverifiedFare
+//	- FareOK otherwise
+//
+// It returns an error if there's no service type, or one of its limits can't be expressed in the fare's currency.
+func CheckFare(fare Money, serviceType *hob.ServiceType) (*FareCheck, error) {
+	if serviceType == nil {
+		return nil, fmt.Errorf("No service type to check the fare against")
+	}
+
+	check := &FareCheck{
+		Fare:    fare,
+		Verdict: FareOK,
+	}
+
+	floor, floorField := serviceType.MinAcceptableFare, "MinAcceptableFare"
+	if floor <= 0 {
+		floor, floorField = serviceType.MinFare, "MinFare"
+	}
+
+	limits := []struct {
+		field   string
+		limit   float64
+		above   bool
+		verdict FareVerdict
+	}{
+		{"MaxFare", serviceType.MaxFare, true, FareAboveMaximum},
+		{floorField, floor, false, FareBelowMinimum},
+		{"MinUnverifiedFare", serviceType.MinUnverifiedFare, false, FareNeedsVerification},
+		{"MinFare", serviceType.MinFare, false, FareNeedsVerification},
+		{"MaxUnverifiedFare", serviceType.MaxUnverifiedFare, true, FareNeedsVerification},
+	}
+
+	for _, l := range limits {
+		if l.limit <= 0 {
+			continue
+		}
+		limit, err := FromMajor(l.limit, fare.Currency, RoundHalfUp)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s for service type %s: %v", l.field, serviceType.Id, err)
+		}
+		if (l.above && fare.Amount > limit.Amount) || (!l.above && fare.Amount < limit.Amount) {
+			check.Verdict = l.verdict
+			check.Limit = limit
+			check.Field = l.field
+			return check, nil
+		}
+	}
+
+	return check, nil
+}
+
+// CheckHobFare checks a fare against the limits of one of the HOB's service types, as CheckFare. The fare must be in
+// the HOB's currency.
+func CheckHobFare(fare Money, hobCode, serviceTypeId string) (*FareCheck, error) {
+	h, err := hob.GetHob(hobCode)
+	if err != nil {
+		return nil, err
+	}
+	if h.Currency != "" {
+		if err := New(0, h.Currency).sameCurrency(fare); err != nil {
+			return nil, err
+		}
+	}
+
+	serviceType, err := hob.GetServiceType(hobCode, serviceTypeId)
+	if err != nil {
+		return nil, err
+	}
+	return CheckFare(fare, serviceType)
+}
@@ -0,0 +1,95 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/HailoOSS/go-hailo-lib/localisation/hob"
+)
+
+func TestCheckFare(t *testing.T) {
+	serviceType := &hob.ServiceType{
+		Id:                "REGULAR",
+		MinAcceptableFare: 2.20,
+		MinUnverifiedFare: 3.00,
+		MinFare:           2.50,
+		MaxUnverifiedFare: 50.00,
+		MaxFare:           999.00,
+	}
+
+	testCases := []struct {
+		fare    Money
+		verdict FareVerdict
+		field   string
+	}{
+		{New(1000, "GBP"), FareOK, ""},
+		{New(300, "GBP"), FareOK, ""},
+		{New(5000, "GBP"), FareOK, ""},
+		{New(219, "GBP"), FareBelowMinimum, "MinAcceptableFare"},
+		{New(220, "GBP"), FareNeedsVerification, "MinUnverifiedFare"},
+		{New(299, "GBP"), FareNeedsVerification, "MinUnverifiedFare"},
+		{New(5001, "GBP"), FareNeedsVerification, "MaxUnverifiedFare"},
+		{New(99900, "GBP"), FareNeedsVerification, "MaxUnverifiedFare"},
+		{New(99901, "GBP"), FareAboveMaximum, "MaxFare"},
+		{New(999, "JPY"), FareNeedsVerification, "MaxUnverifiedFare"},
+		{New(1000, "JPY"), FareAboveMaximum, "MaxFare"},
+		{New(1, "JPY"), FareBelowMinimum, "MinAcceptableFare"},
+	}
+
+	for _, tc := range testCases {
+		check, err := CheckFare(tc.fare, serviceType)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tc.verdict, check.Verdict, "Checking %v: %v", tc.fare, check)
+			assert.Equal(t, tc.field, check.Field, "Checking %v: %v", tc.fare, check)
+		}
+	}
+
+	check, _ := CheckFare(New(219, "GBP"), serviceType)
+	assert.Equal(t, New(220, "GBP"), check.Limit)
+	assert.Equal(t, "2.19 GBP: BELOW_MINIMUM (MinAcceptableFare 2.20 GBP)", check.String())
+}
+
+func TestCheckFareUnsetLimits(t *testing.T) {
+	check, err := CheckFare(New(1, "GBP"), &hob.ServiceType{})
+	assert.NoError(t, err)
+	assert.Equal(t, FareOK, check.Verdict)
+
+	// Without MinAcceptableFare, MinFare is the floor
+	check, err = CheckFare(New(249, "GBP"), &hob.ServiceType{MinFare: 2.50})
+	assert.NoError(t, err)
+	assert.Equal(t, FareBelowMinimum, check.Verdict)
+	assert.Equal(t, "MinFare", check.Field)
+
+	// With it, fares below MinFare need verification
+	check, err = CheckFare(New(249, "GBP"), &hob.ServiceType{MinFare: 2.50, MinAcceptableFare: 2.00})
+	assert.NoError(t, err)
+	assert.Equal(t, FareNeedsVerification, check.Verdict)
+	assert.Equal(t, "MinFare", check.Field)
+}
+
+func TestCheckFareWithoutServiceType(t *testing.T) {
+	check, err := CheckFare(New(1000, "GBP"), nil)
+	assert.Error(t, err)
+	assert.Nil(t, check)
+}
+
+func TestCheckHobFare(t *testing.T) {
+	mockCache := &hob.MockHobsCache{}
+	mockCache.On("ReadHob", "LON").Return(&hob.Hob{Code: "LON", Currency: "GBP"})
+	mockCache.On("ReadServiceTypes", "LON").Return(hob.ServiceTypes{
+		"REGULAR": &hob.ServiceType{Id: "REGULAR", MaxFare: 999.00},
+	})
+	hob.Cache = mockCache
+
+	check, err := CheckHobFare(New(100000, "GBP"), "LON", "REGULAR")
+	if assert.NoError(t, err) {
+		assert.Equal(t, FareAboveMaximum, check.Verdict)
+	}
+
+	_, err = CheckHobFare(New(1000, "EUR"), "LON", "REGULAR")
+	assert.Equal(t, CurrencyMismatchError{Expected: "GBP", Actual: "EUR"}, err)
+
+	_, err = CheckHobFare(New(1000, "GBP"), "LON", "MISSING")
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	c "github.com/HailoOSS/i18n-go/currency"
+)
+
+// RoundingMode determines how amounts falling between two minor units are rounded
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds halves away from zero, eg: 2.5 -> 3, -2.5 -> -3
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven (banker's rounding) rounds halves to the nearest even minor unit, eg: 2.5 -> 2, 3.5 -> 4
+	RoundHalfEven
+)
+
+// CurrencyDigits returns the number of minor unit digits the currency has, eg: 2 for GBP (pence), 0 for JPY, taken from
+// i18n-go (as used by FormatMoney and the template filters). Unknown currencies are assumed to have 2.
+func CurrencyDigits(currency string) int {
+	if cur := c.Get(strings.ToUpper(currency)); cur != nil {
+		return cur.DecimalDigits
+	}
+	return 2
+}
+
+// CurrencyMismatchError is returned when operating on amounts in different currencies
+type CurrencyMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+func (e CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("Currency mismatch: expected %s got %s", e.Expected, e.Actual)
+}
+
+// Money is an amount in the minor units of an ISO 4217 currency (eg: pence for GBP). Arithmetic is done on integers,
+// so amounts never suffer from floating point error.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// New returns an amount of the currency, in its minor units
+func New(amount int64, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: strings.ToUpper(currency),
+	}
+}
+
+// FromMajor converts an amount in major units (eg: hob.ServiceType's MinFare of 2.50) to Money, rounding any fraction of
+// a minor unit with mode. The float is read as its shortest decimal representation, so 2.675 rounds as 2.675 rather
+// than 2.67499999...
+func FromMajor(amount float64, currency string, mode RoundingMode) (Money, error) {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	if !ok {
+		return Money{}, fmt.Errorf("Invalid amount: %v", amount)
+	}
+	r.Mul(r, new(big.Rat).SetInt(pow10(CurrencyDigits(currency))))
+	minor, err := round(r, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(minor, currency), nil
+}
+
+// Major returns the amount in major units (eg: pounds for GBP). This is lossy, so should only be used for display or
+// when calling code that hasn't been converted to Money.
+func (m Money) Major() float64 {
+	f, _ := new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(CurrencyDigits(m.Currency))).Float64()
+	return f
+}
+
+// IsZero returns whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative returns whether the amount is less than zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Neg returns the amount negated
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Cmp compares two amounts, returning -1, 0 or +1 as m is less than, equal to or greater than o
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Add returns the sum of two amounts
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("Overflow adding %v to %v", o, m)
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference between two amounts
+func (m Money) Sub(o Money) (Money, error) {
+	if o.Amount == -o.Amount && o.Amount != 0 {
+		return Money{}, fmt.Errorf("Overflow subtracting %v from %v", o, m)
+	}
+	return m.Add(o.Neg())
+}
+
+// Multiply returns the amount multiplied by the ratio numerator/denominator, rounded with mode
+func (m Money) Multiply(numerator, denominator int64, mode RoundingMode) (Money, error) {
+	if denominator == 0 {
+		return Money{}, fmt.Errorf("Cannot multiply by %d/0", numerator)
+	}
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(numerator)), big.NewInt(denominator))
+	amount, err := round(r, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// Percentage returns the given percentage of the amount (eg: a hob.ServiceType's FastPayFeePercentage), rounded with
+// mode
+func (m Money) Percentage(percent int64, mode RoundingMode) (Money, error) {
+	return m.Multiply(percent, 100, mode)
+}
+
+// Allocate splits the amount between len(ratios) parts in proportion to the ratios, without losing or creating any
+// minor units. Minor units left over after the proportional split go to the parts which were rounded down the most
+// (the earliest first, if equal), so eg: 100 allocated 1:1:1 is 34, 33, 33.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, fmt.Errorf("Cannot allocate between no parts")
+	}
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("Cannot allocate with negative ratio %d", ratio)
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+	if total.Sign() == 0 {
+		return nil, fmt.Errorf("Cannot allocate with ratios summing to zero")
+	}
+
+	// Work with the magnitude, so negative amounts are split the same way as positive ones
+	amount := new(big.Int).Abs(big.NewInt(m.Amount))
+	shares := make([]int64, len(ratios))
+	remainders := make([]*big.Int, len(ratios))
+	allocated := int64(0)
+	for i, ratio := range ratios {
+		share, remainder := new(big.Int).QuoRem(new(big.Int).Mul(amount, big.NewInt(ratio)), total, new(big.Int))
+		shares[i] = share.Int64()
+		remainders[i] = remainder
+		allocated += shares[i]
+	}
+
+	leftover := new(big.Int).Sub(amount, big.NewInt(allocated)).Int64()
+	given := make([]bool, len(ratios))
+	for ; leftover > 0; leftover-- {
+		largest := -1
+		for i, remainder := range remainders {
+			if !given[i] && (largest < 0 || remainder.Cmp(remainders[largest]) > 0) {
+				largest = i
+			}
+		}
+		given[largest] = true
+		shares[largest]++
+	}
+
+	result := make([]Money, len(ratios))
+	for i, share := range shares {
+		if m.Amount < 0 {
+			share = -share
+		}
+		result[i] = Money{Amount: share, Currency: m.Currency}
+	}
+	return result, nil
+}
+
+// String returns the amount in major units followed by the currency, eg: "12.34 GBP"
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.decimal(), m.Currency)
+}
+
+func (m Money) decimal() string {
+	digits := CurrencyDigits(m.Currency)
+	sign, amount := "", strconv.FormatInt(m.Amount, 10)
+	if strings.HasPrefix(amount, "-") {
+		sign, amount = "-", amount[1:]
+	}
+	if digits == 0 {
+		return sign + amount
+	}
+	if len(amount) <= digits {
+		amount = strings.Repeat("0", digits-len(amount)+1) + amount
+	}
+	return sign + amount[:len(amount)-digits] + "." + amount[len(amount)-digits:]
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return CurrencyMismatchError{Expected: m.Currency, Actual: o.Currency}
+	}
+	return nil
+}
+
+// round rounds a rational number of minor units to an integer with mode, erroring if it doesn't fit in an int64
+func round(r *big.Rat, mode RoundingMode) (int64, error) {
+	num, den := r.Num(), r.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	// Compare twice the remainder with the denominator to see if we're below, at or above a half
+	half := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(den)
+	roundAway := half > 0 || (half == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1))
+	if rem.Sign() != 0 && roundAway {
+		quo.Add(quo, big.NewInt(int64(rem.Sign())))
+	}
+
+	if !quo.IsInt64() {
+		return 0, fmt.Errorf("Amount %s overflows", r.FloatString(2))
+	}
+	return quo.Int64(), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,157 @@
+package money
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	a, b := New(1050, "gbp"), New(275, "GBP")
+
+	sum, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, New(1325, "GBP"), sum)
+
+	diff, err := b.Sub(a)
+	assert.NoError(t, err)
+	assert.Equal(t, New(-775, "GBP"), diff)
+	assert.True(t, diff.IsNegative())
+
+	cmp, err := a.Cmp(b)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	_, err = a.Add(New(100, "EUR"))
+	assert.Equal(t, CurrencyMismatchError{Expected: "GBP", Actual: "EUR"}, err)
+	_, err = a.Sub(New(100, "EUR"))
+	assert.Error(t, err)
+	_, err = a.Cmp(New(100, "EUR"))
+	assert.Error(t, err)
+
+	_, err = New(math.MaxInt64, "GBP").Add(New(1, "GBP"))
+	assert.Error(t, err, "Expected overflow")
+	_, err = New(0, "GBP").Sub(New(math.MinInt64, "GBP"))
+	assert.Error(t, err, "Expected overflow")
+}
+
+func TestMoneyMultiply(t *testing.T) {
+	testCases := []struct {
+		amount      int64
+		numerator   int64
+		denominator int64
+		mode        RoundingMode
+		expected    int64
+	}{
+		{1000, 3, 2, RoundHalfUp, 1500},
+		{5, 1, 2, RoundHalfUp, 3},
+		{5, 1, 2, RoundHalfEven, 2},
+		{7, 1, 2, RoundHalfEven, 4},
+		{-5, 1, 2, RoundHalfUp, -3},
+		{-5, 1, 2, RoundHalfEven, -2},
+		{-7, 1, 2, RoundHalfEven, -4},
+		{10, 1, 3, RoundHalfUp, 3},
+		{20, 1, 3, RoundHalfEven, 7},
+		{math.MaxInt64, 2, 2, RoundHalfUp, math.MaxInt64},
+	}
+
+	for _, tc := range testCases {
+		result, err := New(tc.amount, "GBP").Multiply(tc.numerator, tc.denominator, tc.mode)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, result.Amount, "%d * %d/%d (mode %d)", tc.amount, tc.numerator,
+			tc.denominator, tc.mode)
+	}
+
+	_, err := New(100, "GBP").Multiply(1, 0, RoundHalfUp)
+	assert.Error(t, err)
+	_, err = New(math.MaxInt64, "GBP").Multiply(2, 1, RoundHalfUp)
+	assert.Error(t, err, "Expected overflow")
+}
+
+func TestMoneyPercentage(t *testing.T) {
+	fee, err := New(1250, "GBP").Percentage(10, RoundHalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, New(125, "GBP"), fee)
+
+	fee, err = New(1225, "GBP").Percentage(10, RoundHalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123), fee.Amount)
+
+	fee, err = New(1225, "GBP").Percentage(10, RoundHalfEven)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(122), fee.Amount)
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	testCases := []struct {
+		amount   int64
+		ratios   []int64
+		expected []int64
+	}{
+		{100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{-100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{5, []int64{3, 7}, []int64{2, 3}},
+		{101, []int64{1, 2}, []int64{34, 67}},
+		{100, []int64{0, 1}, []int64{0, 100}},
+		{0, []int64{1, 1}, []int64{0, 0}},
+		{1, []int64{1, 1, 1}, []int64{1, 0, 0}},
+	}
+
+	for _, tc := range testCases {
+		parts, err := New(tc.amount, "GBP").Allocate(tc.ratios...)
+		assert.NoError(t, err)
+		actual := make([]int64, len(parts))
+		total := int64(0)
+		for i, part := range parts {
+			assert.Equal(t, "GBP", part.Currency)
+			actual[i] = part.Amount
+			total += part.Amount
+		}
+		assert.Equal(t, tc.expected, actual, "Allocating %d by %v", tc.amount, tc.ratios)
+		assert.Equal(t, tc.amount, total, "Allocating %d by %v lost or created money", tc.amount, tc.ratios)
+	}
+
+	_, err := New(100, "GBP").Allocate()
+	assert.Error(t, err)
+	_, err = New(100, "GBP").Allocate(0, 0)
+	assert.Error(t, err)
+	_, err = New(100, "GBP").Allocate(1, -1)
+	assert.Error(t, err)
+}
+
+func TestMoneyMajorUnits(t *testing.T) {
+	testCases := []struct {
+		amount   float64
+		currency string
+		mode     RoundingMode
+		expected int64
+		str      string
+	}{
+		{2.50, "GBP", RoundHalfUp, 250, "2.50 GBP"},
+		{2.675, "GBP", RoundHalfUp, 268, "2.68 GBP"},
+		{2.675, "GBP", RoundHalfEven, 268, "2.68 GBP"},
+		{2.665, "GBP", RoundHalfEven, 266, "2.66 GBP"},
+		{0.05, "EUR", RoundHalfUp, 5, "0.05 EUR"},
+		{-1.5, "USD", RoundHalfUp, -150, "-1.50 USD"},
+		{1500.5, "JPY", RoundHalfUp, 1501, "1501 JPY"},
+		{1500.5, "JPY", RoundHalfEven, 1500, "1500 JPY"},
+		{1.2345, "KWD", RoundHalfUp, 1235, "1.235 KWD"},
+	}
+
+	for _, tc := range testCases {
+		m, err := FromMajor(tc.amount, tc.currency, tc.mode)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, m.Amount, "Converting %v %s", tc.amount, tc.currency)
+		assert.Equal(t, tc.str, m.String())
+	}
+
+	assert.Equal(t, 2.5, New(250, "GBP").Major())
+	assert.Equal(t, float64(1500), New(1500, "JPY").Major())
+	assert.Equal(t, "-0.05 GBP", New(-5, "GBP").String())
+
+	_, err := FromMajor(math.NaN(), "GBP", RoundHalfUp)
+	assert.Error(t, err)
+	_, err = FromMajor(1e30, "GBP", RoundHalfUp)
+	assert.Error(t, err)
+}
@@ -0,0 +1,190 @@
+package money
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	c "github.com/HailoOSS/i18n-go/currency"
+	loc "github.com/HailoOSS/i18n-go/locale"
+)
+
+// numberFormat holds the separators a locale writes numbers with
+type numberFormat struct {
+	decimal string
+	groups  []string // Accepted group separators; the first is the one the locale formats with
+}
+
+var pointDecimal = numberFormat{decimal: ".", groups: []string{","}}
+
+// equivalentGroups are group separators users type interchangeably with the one a locale formats with
+var equivalentGroups = [][]string{
+	{" ", "\u00a0", "\u202f"},
+	{"'", "\u2019"},
+}
+
+// localeNumberFormat returns the number format for a locale of the form "en_GB", "en-GB" or "en", taking its
+// separators from the i18n-go locale data. A bare language uses the format of its own country (eg: "de" as "de_DE"),
+// and unknown locales use pointDecimal.
+func localeNumberFormat(locale string) numberFormat {
+	locale = strings.Replace(strings.TrimSpace(locale), "-", "_", -1)
+	parts := strings.SplitN(locale, "_", 2)
+	lang := strings.ToLower(parts[0])
+	country := strings.ToUpper(lang)
+	if len(parts) == 2 {
+		country = strings.ToUpper(parts[1])
+	}
+
+	l := loc.Get(lang + "_" + country)
+	if l == nil || l.CurrencyDecimalSeparator == "" {
+		return pointDecimal
+	}
+	format := numberFormat{decimal: l.CurrencyDecimalSeparator}
+	if group := l.CurrencyGroupSeparator; group != "" && group != format.decimal {
+		format.groups = []string{group}
+		for _, equivalent := range equivalentGroups {
+			for i, sep := range equivalent {
+				if sep == group {
+					format.groups = append(format.groups, equivalent[:i]...)
+					format.groups = append(format.groups, equivalent[i+1:]...)
+				}
+			}
+		}
+	}
+	return format
+}
+
+// Parse parses an amount of the currency entered by a user in the given locale, such as "12,50", "1.234,56 €" or
+// "£1,234.56", as the inverse of FormatMoney and the templating currency filters. The currency's symbol or ISO code
+// may come before or after the amount.
+//
+// Input which could be read more than one way is rejected rather than guessed at, eg: "12.50" in a locale using "."
+// to group thousands, or "1,234" for a currency with 2 decimal places in a locale using "," as the decimal separator.
+// Currencies i18n-go doesn't know are rejected.
+func Parse(input, locale, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	cur := c.Get(currency)
+	if cur == nil {
+		return Money{}, fmt.Errorf("Unknown currency '%s'", currency)
+	}
+	format := localeNumberFormat(locale)
+
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return Money{}, fmt.Errorf("No amount entered")
+	}
+
+	s, negative := trimSign(s)
+	s = trimCurrency(s, currency, cur.Symbol)
+	if s2, neg := trimSign(s); neg {
+		if negative {
+			return Money{}, fmt.Errorf("Invalid amount '%s': more than one minus sign", input)
+		}
+		s, negative = s2, true
+	}
+	s = strings.TrimFunc(s, unicode.IsSpace)
+	if s == "" {
+		return Money{}, fmt.Errorf("Invalid amount '%s': no digits", input)
+	}
+
+	whole, fraction, err := splitNumber(s, format)
+	if err != nil {
+		return Money{}, fmt.Errorf("Invalid amount '%s': %v", input, err)
+	}
+
+	digits := cur.DecimalDigits
+	if len(fraction) > digits {
+		if digits == 0 {
+			return Money{}, fmt.Errorf("Invalid amount '%s': %s has no decimal places", input, currency)
+		}
+		return Money{}, fmt.Errorf("Invalid amount '%s': %s has only %d decimal places", input, currency, digits)
+	}
+	fraction += strings.Repeat("0", digits-len(fraction))
+
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("Invalid amount '%s': too large", input)
+	}
+	if negative {
+		amount = -amount
+	}
+	return New(amount, currency), nil
+}
+
+func trimSign(s string) (string, bool) {
+	for _, minus := range []string{"-", "\u2212"} {
+		if strings.HasPrefix(s, minus) {
+			return strings.TrimFunc(strings.TrimPrefix(s, minus), unicode.IsSpace), true
+		}
+	}
+	return s, false
+}
+
+// trimCurrency removes the currency's ISO code or symbol from either end of the amount
+func trimCurrency(s, currency, symbol string) string {
+	markers := []string{currency}
+	if symbol != "" && symbol != currency {
+		markers = append(markers, symbol)
+	}
+
+	for _, marker := range markers {
+		upper := strings.ToUpper(s)
+		if strings.HasPrefix(upper, strings.ToUpper(marker)) {
+			return strings.TrimFunc(s[len(marker):], unicode.IsSpace)
+		}
+		if strings.HasSuffix(upper, strings.ToUpper(marker)) {
+			return strings.TrimFunc(s[:len(s)-len(marker)], unicode.IsSpace)
+		}
+	}
+	return s
+}
+
+// splitNumber splits a number into its whole and fractional digits, validating its use of the locale's separators
+func splitNumber(s string, format numberFormat) (whole, fraction string, err error) {
+	integer := s
+	if i := strings.Index(s, format.decimal); i >= 0 {
+		integer, fraction = s[:i], s[i+len(format.decimal):]
+		if strings.Contains(fraction, format.decimal) {
+			return "", "", fmt.Errorf("more than one decimal separator '%s'", format.decimal)
+		}
+		if integer == "" && fraction == "" {
+			return "", "", fmt.Errorf("no digits")
+		}
+		for _, r := range fraction {
+			if r < '0' || r > '9' {
+				return "", "", fmt.Errorf("unexpected '%c' after the decimal separator '%s'", r, format.decimal)
+			}
+		}
+	}
+
+	groups := []string{integer}
+	for _, sep := range format.groups {
+		if strings.Contains(integer, sep) {
+			groups = strings.Split(integer, sep)
+			break
+		}
+	}
+
+	for i, group := range groups {
+		for _, r := range group {
+			if r < '0' || r > '9' {
+				return "", "", fmt.Errorf("unexpected '%c' (the decimal separator is '%s')", r, format.decimal)
+			}
+		}
+		if len(groups) == 1 {
+			break
+		}
+		// Digits must be grouped in threes, or a separator was probably meant as the decimal separator
+		if (i == 0 && (len(group) == 0 || len(group) > 3)) || (i > 0 && len(group) != 3) {
+			return "", "", fmt.Errorf("digits must be grouped in threes (the decimal separator is '%s')",
+				format.decimal)
+		}
+	}
+
+	whole = strings.Join(groups, "")
+	if whole == "" {
+		whole = "0"
+	}
+	return whole, fraction, nil
+}
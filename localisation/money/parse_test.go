@@ -0,0 +1,81 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input    string
+		locale   string
+		currency string
+		expected int64
+	}{
+		{"12.50", "en_GB", "GBP", 1250},
+		{"£12.50", "en_GB", "GBP", 1250},
+		{"£ 1,234.56", "en_GB", "GBP", 123456},
+		{"1,234,567", "en_GB", "GBP", 123456700},
+		{"12", "en_GB", "GBP", 1200},
+		{"12.5", "en_GB", "GBP", 1250},
+		{".50", "en_GB", "GBP", 50},
+		{"-£3.20", "en_GB", "GBP", -320},
+		{"£-3.20", "en_GB", "GBP", -320},
+		{"12.50 GBP", "en_GB", "GBP", 1250},
+		{"gbp12.50", "en-GB", "gbp", 1250},
+		{"12,50", "de_DE", "EUR", 1250},
+		{"1.234,56", "de_DE", "EUR", 123456},
+		{"1.234,56 €", "de", "EUR", 123456},
+		{"€1.234,56", "es_ES", "EUR", 123456},
+		{"1 234,56 €", "fr_FR", "EUR", 123456},
+		{"1\u00a0234,56\u00a0€", "fr_FR", "EUR", 123456},
+		{"1\u202f234,56", "fr_FR", "EUR", 123456},
+		{"1'234.56", "de_CH", "CHF", 123456},
+		{"CHF 12.50", "de_CH", "CHF", 1250},
+		{"1,234.56", "es_MX", "USD", 123456},
+		{"1,234", "ja_JP", "JPY", 1234},
+		{"¥1,234", "ja_JP", "JPY", 1234},
+		{"1.234", "en_US", "KWD", 1234},
+	}
+
+	for _, tc := range testCases {
+		m, err := Parse(tc.input, tc.locale, tc.currency)
+		if assert.NoError(t, err, "Parsing '%s' (%s)", tc.input, tc.locale) {
+			assert.Equal(t, New(tc.expected, tc.currency), m, "Parsing '%s' (%s)", tc.input, tc.locale)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	testCases := []struct {
+		input    string
+		locale   string
+		currency string
+	}{
+		{"", "en_GB", "GBP"},
+		{"£", "en_GB", "GBP"},
+		{"abc", "en_GB", "GBP"},
+		{"12,50", "en_GB", "GBP"},       // Decimal comma in a decimal point locale
+		{"12.50", "de_DE", "EUR"},       // Decimal point in a decimal comma locale
+		{"1,234", "de_DE", "EUR"},       // Too many decimal places, or grouped thousands?
+		{"1.234", "en_GB", "GBP"},       // Too many decimal places, or grouped thousands?
+		{"1.2.3", "en_GB", "GBP"},       // Multiple decimal separators
+		{"1,23,456.00", "en_GB", "GBP"}, // Bad grouping
+		{"1,234.56,7", "en_GB", "GBP"},  // Group separator after the decimal separator
+		{"1.234,56", "en_GB", "GBP"},    // Wrong locale
+		{"$12.50", "en_GB", "GBP"},      // Wrong currency
+		{"12.50 EUR", "en_GB", "GBP"},   // Wrong currency
+		{"--12", "en_GB", "GBP"},        // Multiple signs
+		{"-£-12", "en_GB", "GBP"},       // Multiple signs
+		{"1,234.5", "ja_JP", "JPY"},     // JPY has no decimal places
+		{"99999999999999999999", "en_GB", "GBP"},
+		{"12.50", "en_GB", "XYZ"}, // Unknown currency
+		{"12.50", "en_GB", ""},    // No currency
+	}
+
+	for _, tc := range testCases {
+		_, err := Parse(tc.input, tc.locale, tc.currency)
+		assert.Error(t, err, "Parsing '%s' (%s) should fail", tc.input, tc.locale)
+	}
+}
@@ -0,0 +1,145 @@
+package localisation
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// MultiHobDiscriminator defines a function that can determine all the HOBs a given server request acts upon (eg: a
+// report spanning several cities). If no HOB can be determined, an empty slice should be returned.
+type MultiHobDiscriminator func(req *server.Request) (hobs []string)
+
+// MultiHobMode determines how many of the discriminated HOBs a caller must hold a role in
+type MultiHobMode int
+
+const (
+	// RequireAllHobs requires the caller to have the role in every HOB the request touches
+	RequireAllHobs MultiHobMode = iota
+	// RequireAnyHob requires the caller to have the role in at least one of the HOBs the request touches
+	RequireAnyHob
+)
+
+// newMultiHobDiscriminatorCache memoises the result of a MultiHobDiscriminator (per-request), sharing the
+// implementation of the single HOB cache by caching the HOBs as a comma separated string
+func newMultiHobDiscriminatorCache(discriminatorImpl MultiHobDiscriminator, opts ...AuthoriserOption) *discriminatorCache {
+	return newDiscriminatorCache(func(req *server.Request) string {
+		return strings.Join(uniqueHobs(discriminatorImpl(req)), ",")
+	}, opts...)
+}
+
+type multiHobAuthoriser struct {
+	roles          []string
+	regions        HobRegions
+	mode           MultiHobMode
+	authoriserImpl func([]string) server.Authoriser
+	hobsCache      *discriminatorCache
+}
+
+// CacheStats returns the stats of the authoriser's discriminator cache
+func (ha *multiHobAuthoriser) CacheStats() CacheStats {
+	return ha.hobsCache.cacheStats()
+}
+
+func (ha *multiHobAuthoriser) Authorise(req *server.Request) errors.Error {
+	decision := newAuthDecision(req, ha.roles)
+
+	// As with the single HOB authoriser, a more global role saves us calling the discriminator at all
+	globalErr := ha.authoriserImpl(ha.roles).Authorise(req)
+	if globalErr == nil {
+		log.Tracef("[Multi-HOB authoriser] Matched non-HOB-specific role; not calling the HOB discriminator")
+		decision.allow()
+		return nil
+	}
+
+	joinedHobs, hit := ha.hobsCache.discriminate(req)
+	hobs := splitHobs(joinedHobs)
+	decision.discriminated(hit, hobs...)
+	if len(hobs) == 0 {
+		decision.deny(globalErr)
+		return globalErr
+	}
+
+	denied := make([]string, 0, len(hobs))
+	for _, hob := range hobs {
+		reqRoles := scopedRoles(ha.roles, hob, ha.regions)
+		decision.tried(reqRoles)
+		if err := ha.authoriserImpl(reqRoles).Authorise(req); err != nil {
+			denied = append(denied, hob)
+		} else if ha.mode == RequireAnyHob {
+			decision.allow()
+			return nil
+		}
+	}
+
+	if len(denied) == 0 {
+		decision.allow()
+		return nil
+	}
+
+	err := errors.Forbidden("com.HailoOSS.kernel.auth.badrole",
+		fmt.Sprintf("Not authorised in HOBs: %s", strings.Join(denied, ", ")))
+	decision.deny(err, denied...)
+	return err
+}
+
+func splitHobs(hobs string) []string {
+	if hobs == "" {
+		return nil
+	}
+	return strings.Split(hobs, ",")
+}
+
+func uniqueHobs(hobs []string) []string {
+	seen := make(map[string]bool, len(hobs))
+	result := make([]string, 0, len(hobs))
+	for _, hob := range hobs {
+		if hob != "" && !seen[hob] {
+			seen[hob] = true
+			result = append(result, hob)
+		}
+	}
+	return result
+}
+
+// MultiHobRoleAuthoriser requires a service or user calling an endpoint to have ANY of the roles passed, scoped to
+// the HOBs the request touches (as determined by hobsDiscriminator). Depending on mode the role is required in ALL of
+// the HOBs, or in ANY of them. If the hobsDiscriminator returns no HOBs, the roles are matched unscoped. A role granted
+// for all HOBs ("{{ role }}.*") is always accepted, even if no Regions are configured. The discriminator's results are
+// cached per-request, which can be tuned with opts. Panics if any of the roles end with ".*", so a misconfigured
+// service fails at startup.
+func MultiHobRoleAuthoriser(roles []string, mode MultiHobMode, hobsDiscriminator MultiHobDiscriminator,
+	opts ...AuthoriserOption) server.Authoriser {
+	validateHobRoles(roles)
+	options := newAuthoriserOptions(opts)
+	return &multiHobAuthoriser{
+		roles:          roles,
+		regions:        options.regions,
+		mode:           mode,
+		authoriserImpl: options.roleAuthoriserOr(server.RoleAuthoriser),
+		hobsCache:      newMultiHobDiscriminatorCache(hobsDiscriminator, opts...),
+	}
+}
+
+// SignInMultiHobRoleAuthoriser requires a real person signed in calling an endpoint to have ANY of the roles passed,
+// scoped to the HOBs the request touches (as determined by hobsDiscriminator). Depending on mode the role is required
+// in ALL of the HOBs, or in ANY of them. If the hobsDiscriminator returns no HOBs, the roles are matched unscoped. A
+// role granted for all HOBs ("{{ role }}.*") is always accepted, even if no Regions are configured. The
+// discriminator's results are cached per-request, which can be tuned with opts. Panics if any of the roles end with
+// ".*", so a misconfigured service fails at startup.
+func SignInMultiHobRoleAuthoriser(roles []string, mode MultiHobMode, hobsDiscriminator MultiHobDiscriminator,
+	opts ...AuthoriserOption) server.Authoriser {
+	validateHobRoles(roles)
+	options := newAuthoriserOptions(opts)
+	return &multiHobAuthoriser{
+		roles:          roles,
+		regions:        options.regions,
+		mode:           mode,
+		authoriserImpl: options.roleAuthoriserOr(server.SignInRoleAuthoriser),
+		hobsCache:      newMultiHobDiscriminatorCache(hobsDiscriminator, opts...),
+	}
+}
@@ -0,0 +1,96 @@
+package localisation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// grantedRoles is a server.Authoriser which passes if any of the required roles are granted
+type grantedRoles struct {
+	granted  map[string]bool
+	required []string
+}
+
+func (g *grantedRoles) Authorise(req *server.Request) errors.Error {
+	for _, role := range g.required {
+		if g.granted[role] {
+			return nil
+		}
+	}
+	return errors.Forbidden("com.HailoOSS.kernel.auth.badrole", fmt.Sprintf("Missing roles %v", g.required))
+}
+
+func grantingAuthoriser(granted ...string) func([]string) server.Authoriser {
+	grantedSet := make(map[string]bool, len(granted))
+	for _, role := range granted {
+		grantedSet[role] = true
+	}
+	return func(roles []string) server.Authoriser {
+		return &grantedRoles{grantedSet, roles}
+	}
+}
+
+func TestMultiHobAuthoriser(t *testing.T) {
+	testCases := []struct {
+		granted  []string
+		mode     MultiHobMode
+		hobs     []string
+		ok       bool
+		deniedIn string
+	}{
+		{[]string{"ADMIN.LON", "ADMIN.MAN"}, RequireAllHobs, []string{"LON", "MAN"}, true, ""},
+		{[]string{"ADMIN.LON"}, RequireAllHobs, []string{"LON", "MAN", "DUB"}, false, "MAN, DUB"},
+		{[]string{"ADMIN.LON"}, RequireAnyHob, []string{"LON", "MAN"}, true, ""},
+		{[]string{"ADMIN.NYC"}, RequireAnyHob, []string{"LON", "MAN"}, false, "LON, MAN"},
+		{[]string{"ADMIN"}, RequireAllHobs, []string{"LON", "MAN"}, true, ""},
+		{[]string{"ADMIN.LON"}, RequireAllHobs, []string{"LON", "LON", ""}, true, ""},
+		{[]string{"ADMIN.LON"}, RequireAllHobs, nil, false, ""},
+	}
+
+	for i, tc := range testCases {
+		hobs := tc.hobs
+		ha := &multiHobAuthoriser{
+			roles:          []string{"ADMIN"},
+			mode:           tc.mode,
+			authoriserImpl: grantingAuthoriser(tc.granted...),
+			hobsCache:      newMultiHobDiscriminatorCache(func(req *server.Request) []string { return hobs }),
+		}
+
+		err := ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: fmt.Sprintf("%d", i)}))
+		if tc.ok {
+			assert.Nil(t, err, "Expected case %d to be authorised", i)
+			continue
+		}
+		if assert.NotNil(t, err, "Expected case %d to be denied", i) && tc.deniedIn != "" {
+			assert.Contains(t, err.Description(), tc.deniedIn)
+		}
+	}
+}
+
+func TestMultiHobDiscriminatorCache(t *testing.T) {
+	times := 0
+	cache := newMultiHobDiscriminatorCache(func(req *server.Request) []string {
+		times++
+		if req.MessageID() == "none" {
+			return nil
+		}
+		return []string{"LON", "MAN", "LON"}
+	})
+
+	req := server.NewRequestFromDelivery(amqp.Delivery{MessageId: "1"})
+	for i := 0; i < 10; i++ {
+		hobs, hit := cache.discriminate(req)
+		assert.Equal(t, []string{"LON", "MAN"}, splitHobs(hobs))
+		assert.Equal(t, i > 0, hit)
+	}
+	assert.Equal(t, 1, times, "Wasn't cached")
+
+	hobs, _ := cache.discriminate(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "none"}))
+	assert.Empty(t, splitHobs(hobs))
+}
@@ -0,0 +1,107 @@
+package localisation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HobRegions groups HOBs into named regions, so a role can be granted over a whole region rather than HOB by HOB.
+// Regions may contain other regions, eg:
+//
+//	HobRegions{
+//		"UK": {"LON", "MAN"},
+//		"EU": {"UK", "DUB", "MAD"},
+//	}
+//
+// means "ADMIN.UK.*" grants ADMIN in LON and MAN, and "ADMIN.EU.*" grants it in all four HOBs.
+type HobRegions map[string][]string
+
+// RegionsOf returns every region the HOB belongs to, directly or through another region, most specific first
+func (r HobRegions) RegionsOf(hob string) []string {
+	result := make([]string, 0)
+	seen := make(map[string]bool)
+
+	// Breadth first, so nearer regions come before those containing them
+	current := []string{hob}
+	for len(current) > 0 {
+		next := make([]string, 0)
+		for _, member := range current {
+			for _, region := range r.containing(member) {
+				if !seen[region] {
+					seen[region] = true
+					result = append(result, region)
+					next = append(next, region)
+				}
+			}
+		}
+		current = next
+	}
+
+	return result
+}
+
+// containing returns the regions directly containing the HOB or region, in name order so results are stable
+func (r HobRegions) containing(member string) []string {
+	regions := make([]string, 0)
+	for region, members := range r {
+		for _, m := range members {
+			if m == member {
+				regions = append(regions, region)
+				break
+			}
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// Validate checks no region contains itself (directly or indirectly), and that region names can't be mistaken for
+// part of a role
+func (r HobRegions) Validate() error {
+	for region := range r {
+		if region == "" || strings.ContainsAny(region, ".*") {
+			return fmt.Errorf("Invalid region name '%s'", region)
+		}
+		for _, containing := range r.RegionsOf(region) {
+			if containing == region {
+				return fmt.Errorf("Region '%s' contains itself", region)
+			}
+		}
+	}
+	return nil
+}
+
+// Regions makes the authoriser accept roles granted over a region containing the request's HOB, of the form
+// "{{ role }}.{{ region }}.*" (global grants of the form "{{ role }}.*" are accepted with or without regions). Panics if
+// the regions are invalid.
+func Regions(regions HobRegions) AuthoriserOption {
+	if err := regions.Validate(); err != nil {
+		panic(err.Error())
+	}
+	return func(o *authoriserOptions) {
+		o.regions = regions
+	}
+}
+
+// scopedRoles returns the roles which grant the caller access to the HOB: the roles scoped to the HOB itself, then
+// (if regions are in use) to each region containing it, then globally.
+//
+// These are handed to the role authoriser as plain role names, which it's assumed to match literally: a caller is
+// granted "ADMIN.UK.*" by holding a role named exactly that, not by the authoriser treating it as a pattern.
+func scopedRoles(roles []string, hob string, regions HobRegions) []string {
+	if hob == "" {
+		return roles
+	}
+
+	hobRegions := regions.RegionsOf(hob)
+	reqRoles := make([]string, 0, len(roles)*(len(hobRegions)+2))
+	for _, role := range roles {
+		reqRoles = append(reqRoles, fmt.Sprintf("%s.%s", role, hob))
+		for _, region := range hobRegions {
+			reqRoles = append(reqRoles, fmt.Sprintf("%s.%s.*", role, region))
+		}
+		reqRoles = append(reqRoles, fmt.Sprintf("%s.*", role))
+	}
+	return reqRoles
+}
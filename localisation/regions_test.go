@@ -0,0 +1,135 @@
+package localisation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/HailoOSS/platform/server"
+)
+
+var testRegions = HobRegions{
+	"UK":   {"LON", "MAN"},
+	"IE":   {"DUB"},
+	"EU":   {"UK", "IE", "MAD"},
+	"CITY": {"LON", "NYC"},
+}
+
+func TestRegionsOf(t *testing.T) {
+	assert.Equal(t, []string{"CITY", "UK", "EU"}, testRegions.RegionsOf("LON"))
+	assert.Equal(t, []string{"IE", "EU"}, testRegions.RegionsOf("DUB"))
+	assert.Equal(t, []string{"EU"}, testRegions.RegionsOf("MAD"))
+	assert.Equal(t, []string{"EU"}, testRegions.RegionsOf("UK"))
+	assert.Empty(t, testRegions.RegionsOf("TYO"))
+	assert.Empty(t, HobRegions(nil).RegionsOf("LON"))
+}
+
+func TestValidateRegions(t *testing.T) {
+	assert.NoError(t, testRegions.Validate())
+	assert.Error(t, HobRegions{"UK": {"LON", "UK"}}.Validate())
+	assert.Error(t, HobRegions{"UK": {"EU"}, "EU": {"UK"}}.Validate())
+	assert.Error(t, HobRegions{"U.K": {"LON"}}.Validate())
+	assert.Error(t, HobRegions{"*": {"LON"}}.Validate())
+
+	assert.Panics(t, func() {
+		Regions(HobRegions{"UK": {"UK"}})
+	})
+}
+
+func TestScopedRoles(t *testing.T) {
+	roles := []string{"ADMIN", "OPS"}
+
+	assert.Equal(t, roles, scopedRoles(roles, "", testRegions))
+	assert.Equal(t, []string{"ADMIN.LON", "ADMIN.*", "OPS.LON", "OPS.*"}, scopedRoles(roles, "LON", nil))
+	assert.Equal(t, []string{
+		"ADMIN.DUB", "ADMIN.IE.*", "ADMIN.EU.*", "ADMIN.*",
+		"OPS.DUB", "OPS.IE.*", "OPS.EU.*", "OPS.*",
+	}, scopedRoles(roles, "DUB", testRegions))
+}
+
+func TestHobAuthoriserRegions(t *testing.T) {
+	testCases := []struct {
+		granted string
+		hob     string
+		ok      bool
+	}{
+		{"ADMIN.LON", "LON", true},
+		{"ADMIN.UK.*", "LON", true},
+		{"ADMIN.UK.*", "MAN", true},
+		{"ADMIN.UK.*", "DUB", false},
+		{"ADMIN.EU.*", "DUB", true},
+		{"ADMIN.*", "NYC", true},
+		{"ADMIN.UK", "LON", false},
+		{"OPS.UK.*", "LON", false},
+	}
+
+	for i, tc := range testCases {
+		ha := &hobAuthoriser{
+			roles:          []string{"ADMIN"},
+			regions:        newAuthoriserOptions([]AuthoriserOption{Regions(testRegions)}).regions,
+			authoriserImpl: grantingAuthoriser(tc.granted),
+			hobCache:       newDiscriminatorCache(StaticDiscriminator(tc.hob)),
+		}
+		err := ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: fmt.Sprintf("%d", i)}))
+		assert.Equal(t, tc.ok, err == nil, "Incorrect outcome for %s in %s", tc.granted, tc.hob)
+	}
+}
+
+func TestHobAuthoriserGlobalWithoutRegions(t *testing.T) {
+	ha := HobRoleAuthoriser([]string{"ADMIN"}, StaticDiscriminator("LON"),
+		RoleAuthoriserImpl(grantingAuthoriser("ADMIN.*")))
+	assert.Nil(t, ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "global"})))
+
+	ha = HobRoleAuthoriser([]string{"ADMIN"}, StaticDiscriminator("LON"),
+		RoleAuthoriserImpl(grantingAuthoriser("ADMIN.UK.*")))
+	assert.NotNil(t, ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "region"})))
+
+	ha = SignInHobRoleAuthoriser([]string{"ADMIN"}, StaticDiscriminator("LON"),
+		RoleAuthoriserImpl(grantingAuthoriser("ADMIN.*")))
+	assert.Nil(t, ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "signin"})))
+}
+
+func TestMultiHobAuthoriserGlobalWithoutRegions(t *testing.T) {
+	hobs := func(req *server.Request) []string { return []string{"LON", "DUB"} }
+
+	ha := MultiHobRoleAuthoriser([]string{"ADMIN"}, RequireAllHobs, hobs,
+		RoleAuthoriserImpl(grantingAuthoriser("ADMIN.*")))
+	assert.Nil(t, ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "global"})))
+
+	ha = SignInMultiHobRoleAuthoriser([]string{"ADMIN"}, RequireAllHobs, hobs,
+		RoleAuthoriserImpl(grantingAuthoriser("ADMIN.UK.*", "ADMIN.DUB")))
+	assert.NotNil(t, ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "region"})))
+}
+
+func TestAuthorisersValidateRoles(t *testing.T) {
+	roles := []string{"ADMIN", "OPS.*"}
+	assert.Panics(t, func() { HobRoleAuthoriser(roles, StaticDiscriminator("LON")) })
+	assert.Panics(t, func() { SignInHobRoleAuthoriser(roles, StaticDiscriminator("LON")) })
+	assert.Panics(t, func() { MultiHobRoleAuthoriser(roles, RequireAllHobs, nil) })
+	assert.Panics(t, func() { SignInMultiHobRoleAuthoriser(roles, RequireAnyHob, nil) })
+	assert.NotPanics(t, func() { HobRoleAuthoriser([]string{"ADMIN"}, StaticDiscriminator("LON")) })
+}
+
+func TestMultiHobAuthoriserRegions(t *testing.T) {
+	ha := &multiHobAuthoriser{
+		roles:          []string{"ADMIN"},
+		regions:        testRegions,
+		mode:           RequireAllHobs,
+		authoriserImpl: grantingAuthoriser("ADMIN.UK.*", "ADMIN.DUB"),
+		hobsCache: newMultiHobDiscriminatorCache(func(req *server.Request) []string {
+			if req.MessageID() == "uk" {
+				return []string{"LON", "MAN", "DUB"}
+			}
+			return []string{"LON", "MAD"}
+		}),
+	}
+
+	assert.Nil(t, ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "uk"})))
+	err := ha.Authorise(server.NewRequestFromDelivery(amqp.Delivery{MessageId: "eu"}))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Description(), "MAD")
+		assert.NotContains(t, err.Description(), "LON")
+	}
+}
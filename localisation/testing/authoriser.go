@@ -0,0 +1,58 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+type roleAuthoriser struct {
+	roles         []string
+	requireSignIn bool
+}
+
+// RoleAuthoriser is a fake of server.RoleAuthoriser, which requires the caller to hold ANY of the roles passed, as set
+// on the request with WithRoles. Roles must match exactly; there's no inheritance between them.
+func RoleAuthoriser(roles []string) server.Authoriser {
+	return &roleAuthoriser{roles: roles}
+}
+
+// SignInRoleAuthoriser is a fake of server.SignInRoleAuthoriser, which additionally requires the request to have been
+// made with a session (see WithSession)
+func SignInRoleAuthoriser(roles []string) server.Authoriser {
+	return &roleAuthoriser{roles: roles, requireSignIn: true}
+}
+
+func (a *roleAuthoriser) Authorise(req *server.Request) errors.Error {
+	if a.requireSignIn && header(req, sessionHeader) == "" {
+		return errors.Unauthorized("com.HailoOSS.kernel.auth.notsignedin", "Must be signed in to access this endpoint")
+	}
+
+	held := Roles(req)
+	for _, role := range a.roles {
+		for _, h := range held {
+			if role == h {
+				return nil
+			}
+		}
+	}
+
+	return errors.Forbidden("com.HailoOSS.kernel.auth.badrole",
+		fmt.Sprintf("Must have one of the roles: %s", strings.Join(a.roles, ", ")))
+}
+
+// Roles returns the roles set on a request built with WithRoles
+func Roles(req *server.Request) []string {
+	roles := header(req, RolesHeader)
+	if roles == "" {
+		return nil
+	}
+	return strings.Split(roles, ",")
+}
+
+func header(req *server.Request, key string) string {
+	value, _ := req.Headers()[key].(string)
+	return value
+}
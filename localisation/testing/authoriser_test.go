@@ -0,0 +1,64 @@
+package testing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestBuilder(t *testing.T) {
+	req := NewRequest().
+		WithSession("abc123").
+		WithRoles("ADMIN.LON", "CUSTOMER").
+		WithHob("LON").
+		WithService("com.HailoOSS.service.job").
+		WithEndpoint("create").
+		Build()
+
+	assert.NotEmpty(t, req.MessageID())
+	assert.Equal(t, "abc123", req.SessionID())
+	assert.Equal(t, []string{"ADMIN.LON", "CUSTOMER"}, Roles(req))
+	assert.Equal(t, "LON", req.Headers()[HobHeader])
+	assert.Equal(t, "com.HailoOSS.service.job", req.Service())
+	assert.Equal(t, "create", req.Endpoint())
+
+	assert.NotEqual(t, req.MessageID(), NewRequest().Build().MessageID(), "Message IDs should be unique")
+	assert.Equal(t, "1", NewRequest().WithMessageID("1").Build().MessageID())
+	assert.Nil(t, Roles(NewRequest().Build()))
+}
+
+func TestRoleAuthoriser(t *testing.T) {
+	testCases := []struct {
+		roles      []string
+		held       []string
+		session    string
+		authorised bool
+	}{
+		{[]string{"ADMIN"}, []string{"ADMIN"}, "", true},
+		{[]string{"ADMIN"}, []string{"ADMIN"}, "abc123", true},
+		{[]string{"ADMIN", "OPS"}, []string{"CUSTOMER", "OPS"}, "abc123", true},
+		{[]string{"ADMIN"}, []string{"ADMIN.LON"}, "abc123", false},
+		{[]string{"ADMIN.LON"}, []string{"ADMIN"}, "abc123", false},
+		{[]string{"ADMIN"}, nil, "abc123", false},
+	}
+
+	for _, tc := range testCases {
+		req := NewRequest().WithRoles(tc.held...).WithSession(tc.session).Build()
+
+		err := RoleAuthoriser(tc.roles).Authorise(req)
+		assert.Equal(t, tc.authorised, err == nil, "RoleAuthoriser(%v) with roles %v", tc.roles, tc.held)
+
+		err = SignInRoleAuthoriser(tc.roles).Authorise(req)
+		assert.Equal(t, tc.authorised && tc.session != "", err == nil, "SignInRoleAuthoriser(%v) with roles %v",
+			tc.roles, tc.held)
+	}
+
+	err := SignInRoleAuthoriser([]string{"ADMIN"}).Authorise(NewRequest().WithRoles("ADMIN").Build())
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "com.HailoOSS.kernel.auth.notsignedin", err.Code())
+	}
+	err = RoleAuthoriser([]string{"ADMIN"}).Authorise(NewRequest().Build())
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "com.HailoOSS.kernel.auth.badrole", err.Code())
+	}
+}
@@ -0,0 +1,115 @@
+// Package testing provides fakes for exercising the HOB authorisers in the localisation package without a running
+// platform. Requests are built with NewRequest, and the fake RoleAuthoriser/SignInRoleAuthoriser are passed to the
+// authorisers with localisation.RoleAuthoriserImpl, eg:
+//
+//	authoriser := localisation.HobRoleAuthoriser([]string{"ADMIN"}, localisation.HeaderDiscriminator(testing.HobHeader),
+//		localisation.RoleAuthoriserImpl(testing.RoleAuthoriser))
+//	err := authoriser.Authorise(testing.NewRequest().WithRoles("ADMIN.LON").WithHob("LON").Build())
+//
+// As the package name clashes with the standard library, it's usually imported under another name (eg: loctesting).
+package testing
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/streadway/amqp"
+
+	"github.com/HailoOSS/platform/server"
+	"github.com/HailoOSS/protobuf/proto"
+)
+
+const (
+	// HobHeader is the header WithHob sets, for use with localisation.HeaderDiscriminator
+	HobHeader = "hob"
+	// RolesHeader is the header WithRoles sets, and the fake role authorisers read the caller's roles from
+	RolesHeader = "x-test-roles"
+
+	sessionHeader  = "sessionID"
+	serviceHeader  = "service"
+	endpointHeader = "endpoint"
+	fromHeader     = "from"
+)
+
+var messageIDs uint64
+
+// RequestBuilder builds a *server.Request, as would be received by a service
+type RequestBuilder struct {
+	delivery amqp.Delivery
+	err      error
+}
+
+// NewRequest returns a builder for a request with a unique message ID (so HOB discriminator results aren't shared
+// between requests), and no session, roles or HOB
+func NewRequest() *RequestBuilder {
+	return &RequestBuilder{
+		delivery: amqp.Delivery{
+			MessageId: fmt.Sprintf("test-%d", atomic.AddUint64(&messageIDs, 1)),
+			Headers:   amqp.Table{},
+		},
+	}
+}
+
+// WithMessageID overrides the request's message ID
+func (b *RequestBuilder) WithMessageID(id string) *RequestBuilder {
+	b.delivery.MessageId = id
+	return b
+}
+
+// WithSession sets the session the request was made with, as required by the fake SignInRoleAuthoriser
+func (b *RequestBuilder) WithSession(sessionID string) *RequestBuilder {
+	return b.WithHeader(sessionHeader, sessionID)
+}
+
+// WithRoles sets the roles the caller holds, as checked by the fake role authorisers. Roles are scoped to a HOB or
+// region in the same way as real ones, eg: "ADMIN.LON", "ADMIN.UK.*" or "ADMIN.*".
+func (b *RequestBuilder) WithRoles(roles ...string) *RequestBuilder {
+	return b.WithHeader(RolesHeader, strings.Join(roles, ","))
+}
+
+// WithHob sets the HobHeader on the request
+func (b *RequestBuilder) WithHob(hob string) *RequestBuilder {
+	return b.WithHeader(HobHeader, hob)
+}
+
+// WithService sets the service the request was sent to
+func (b *RequestBuilder) WithService(service string) *RequestBuilder {
+	return b.WithHeader(serviceHeader, service)
+}
+
+// WithEndpoint sets the endpoint the request was sent to
+func (b *RequestBuilder) WithEndpoint(endpoint string) *RequestBuilder {
+	return b.WithHeader(endpointHeader, endpoint)
+}
+
+// WithFrom sets the service the request was sent from
+func (b *RequestBuilder) WithFrom(from string) *RequestBuilder {
+	return b.WithHeader(fromHeader, from)
+}
+
+// WithHeader sets an arbitrary header on the request
+func (b *RequestBuilder) WithHeader(key, value string) *RequestBuilder {
+	b.delivery.Headers[key] = value
+	return b
+}
+
+// WithPayload sets the request's body to the marshalled message, for use with proto field discriminators
+func (b *RequestBuilder) WithPayload(msg proto.Message) *RequestBuilder {
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.delivery.Body = body
+	b.delivery.ContentType = "application/octetstream"
+	return b
+}
+
+// Build returns the request. Panics if the payload couldn't be marshalled.
+func (b *RequestBuilder) Build() *server.Request {
+	if b.err != nil {
+		panic(fmt.Sprintf("Failed to build test request: %v", b.err))
+	}
+	return server.NewRequestFromDelivery(b.delivery)
+}
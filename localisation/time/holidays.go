@@ -0,0 +1,423 @@
+package time
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HailoOSS/go-hailo-lib/localisation/hob"
+)
+
+//go:generate go run holidays_gen.go
+
+// Holiday is a public holiday
+type Holiday struct {
+	Name string
+	Date time.Time // Midnight at the start of the holiday, in the calendar's location
+}
+
+// Calendar answers whether dates are public holidays (or working days) in a country, in a given location
+type Calendar struct {
+	Country  string
+	location *time.Location
+	rules    []holidayRule
+
+	mtx   sync.Mutex
+	years map[int]map[date]string
+}
+
+var (
+	countryRules     = make(map[string][]holidayRule)
+	countryRulesLock sync.Mutex
+)
+
+// calendarKey identifies a calendar. HOB locations are memoised, so the same HOB's calendar is found by pointer.
+type calendarKey struct {
+	country  string
+	location *time.Location
+}
+
+var (
+	calendars     = make(map[calendarKey]*Calendar)
+	calendarsLock sync.Mutex
+)
+
+// NewCalendar returns the holiday calendar for a country (by ISO 3166-1 alpha-2 code), deciding which day times fall
+// on in the location. Calendars are shared, so the holidays of each year are only worked out once per country and
+// location.
+func NewCalendar(country string, location *time.Location) (*Calendar, error) {
+	country = strings.ToUpper(country)
+	if location == nil {
+		location = time.UTC
+	}
+	key := calendarKey{country, location}
+
+	calendarsLock.Lock()
+	defer calendarsLock.Unlock()
+	if c, ok := calendars[key]; ok {
+		return c, nil
+	}
+
+	rules, err := rulesFor(country)
+	if err != nil {
+		return nil, err
+	}
+	c := &Calendar{
+		Country:  country,
+		location: location,
+		rules:    rules,
+		years:    make(map[int]map[date]string),
+	}
+	calendars[key] = c
+	return c, nil
+}
+
+// HobCalendar returns the holiday calendar for the HOB's country, in the HOB's timezone
+func HobCalendar(h *hob.Hob) (*Calendar, error) {
+	location, err := h.Location()
+	if err != nil {
+		return nil, err
+	}
+	return NewCalendar(h.Country.ISO_3166_1, location)
+}
+
+// GetHobCalendar returns the holiday calendar for the HOB (by code), as HobCalendar
+func GetHobCalendar(hobCode string) (*Calendar, error) {
+	h, err := hob.GetHob(hobCode)
+	if err != nil {
+		return nil, err
+	}
+	return HobCalendar(h)
+}
+
+func rulesFor(country string) ([]holidayRule, error) {
+	countryRulesLock.Lock()
+	defer countryRulesLock.Unlock()
+
+	if rules, ok := countryRules[country]; ok {
+		return rules, nil
+	}
+	data, ok := holidayData[country]
+	if !ok {
+		return nil, fmt.Errorf("No holiday calendar for country '%s'", country)
+	}
+	rules, err := parseHolidayRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid holiday calendar for country '%s': %v", country, err)
+	}
+	countryRules[country] = rules
+	return rules, nil
+}
+
+// Location returns the location the calendar decides days in
+func (c *Calendar) Location() *time.Location {
+	return c.location
+}
+
+// HolidayName returns the name of the holiday on the day t falls on (in the calendar's location), or "" if it's not a
+// holiday
+func (c *Calendar) HolidayName(t time.Time) string {
+	d := dateOf(t.In(c.location))
+	// Holidays substituted at the start or end of a year can fall in a neighbouring one
+	for _, year := range []int{d.year, d.year - 1, d.year + 1} {
+		if name, ok := c.holidays(year)[d]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// IsHoliday returns whether the day t falls on (in the calendar's location) is a public holiday
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	return c.HolidayName(t) != ""
+}
+
+// IsWorkingDay returns whether the day t falls on (in the calendar's location) is a weekday which isn't a holiday
+func (c *Calendar) IsWorkingDay(t time.Time) bool {
+	t = t.In(c.location)
+	return !isWeekend(t.Weekday()) && !c.IsHoliday(t)
+}
+
+// NextWorkingDay returns midnight at the start of the first working day after the day t falls on, in the calendar's
+// location
+func (c *Calendar) NextWorkingDay(t time.Time) time.Time {
+	d := dateOf(t.In(c.location))
+	for {
+		d = d.addDays(1)
+		if midnight := d.in(c.location); c.IsWorkingDay(midnight) {
+			return midnight
+		}
+	}
+}
+
+// Holidays returns the holidays in a year, in date order
+func (c *Calendar) Holidays(year int) []Holiday {
+	result := make([]Holiday, 0)
+	for _, y := range []int{year - 1, year, year + 1} {
+		for d, name := range c.holidays(y) {
+			if d.year == year {
+				result = append(result, Holiday{Name: name, Date: d.in(c.location)})
+			}
+		}
+	}
+	sort.Sort(holidaysByDate(result))
+	return result
+}
+
+// holidays returns the holidays generated by the rules for a year
+func (c *Calendar) holidays(year int) map[date]string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if holidays, ok := c.years[year]; ok {
+		return holidays
+	}
+
+	holidays := make(map[date]string, len(c.rules))
+	substitutes := make([]holidayRule, 0)
+	for _, rule := range c.rules {
+		d := rule.date(year)
+		if _, taken := holidays[d]; !taken {
+			holidays[d] = rule.name
+		}
+		if rule.observance != observeOnDay && isWeekend(d.weekday()) {
+			substitutes = append(substitutes, rule)
+		}
+	}
+
+	// Substitute days are worked out once the year's other holidays are known, so they don't clash
+	for _, rule := range substitutes {
+		d := rule.date(year)
+		if rule.observance == observeNearestWeekday {
+			if d.weekday() == time.Saturday {
+				d = d.addDays(-1)
+			} else {
+				d = d.addDays(1)
+			}
+			holidays[d] = rule.name + " (observed)"
+			continue
+		}
+		for isWeekend(d.weekday()) || holidays[d] != "" {
+			d = d.addDays(1)
+		}
+		holidays[d] = rule.name + " (substitute day)"
+	}
+
+	c.years[year] = holidays
+	return holidays
+}
+
+func isWeekend(day time.Weekday) bool {
+	return day == time.Saturday || day == time.Sunday
+}
+
+type holidaysByDate []Holiday
+
+func (h holidaysByDate) Len() int           { return len(h) }
+func (h holidaysByDate) Less(i, j int) bool { return h[i].Date.Before(h[j].Date) }
+func (h holidaysByDate) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+// date is a calendar date, independent of location
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func dateOf(t time.Time) date {
+	y, m, d := t.Date()
+	return date{y, m, d}
+}
+
+// normalised handles days outside of the month (eg: 32nd January is 1st February)
+func (d date) normalised() date {
+	return dateOf(time.Date(d.year, d.month, d.day, 12, 0, 0, 0, time.UTC))
+}
+
+func (d date) addDays(n int) date {
+	return date{d.year, d.month, d.day + n}.normalised()
+}
+
+func (d date) weekday() time.Weekday {
+	return time.Date(d.year, d.month, d.day, 12, 0, 0, 0, time.UTC).Weekday()
+}
+
+// in returns the first instant of the date in the location (usually, but not always, midnight)
+func (d date) in(location *time.Location) time.Time {
+	return time.Date(d.year, d.month, d.day, 0, 0, 0, 0, location)
+}
+
+type observance int
+
+const (
+	observeOnDay observance = iota
+	observeNextWorkingDay
+	observeNearestWeekday
+)
+
+type ruleKind int
+
+const (
+	fixedRule ruleKind = iota
+	nthWeekdayRule
+	lastWeekdayRule
+	weekdayBeforeRule
+	easterRule
+)
+
+type holidayRule struct {
+	name       string
+	kind       ruleKind
+	month      time.Month
+	day        int // Day of the month (fixedRule, weekdayBeforeRule), N (nthWeekdayRule), or days from Easter
+	weekday    time.Weekday
+	observance observance
+}
+
+var weekdays = map[string]time.Weekday{
+	"SUN": time.Sunday,
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+}
+
+// parseHolidayRules parses a country's holiday data file (holidays/<country>.txt). Each line is a rule followed by the
+// holiday's name, where the rule is one of:
+//
+//	MM-DD       a fixed date, eg: 12-25
+//	MM-DD+      a fixed date, substituted by the next working day if it falls at the weekend (eg: UK bank holidays)
+//	MM-DD~      a fixed date, observed on the Friday before or Monday after if it falls at the weekend (eg: US federal
+//	            holidays)
+//	MM-DOW-N    the Nth weekday of the month, eg: 11-THU-4 for the fourth Thursday of November
+//	MM-DOW-L    the last weekday of the month, eg: 05-MON-L for the last Monday of May
+//	MM-DOW<DD   the last weekday of the month before the day, eg: 05-MON<25 for the last Monday before 25th May
+//	E+N / E-N   N days after/before Easter Sunday, eg: E-2 for Good Friday
+//
+// Blank lines, and those starting with "#", are ignored. Only holidays observed nationally are listed (GB being those
+// of England and Wales).
+func parseHolidayRules(data string) ([]holidayRule, error) {
+	rules := make([]holidayRule, 0)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("Rule '%s' has no name", line)
+		}
+		rule, err := parseHolidayRule(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		rule.name = strings.TrimSpace(parts[1])
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseHolidayRule(s string) (holidayRule, error) {
+	invalid := fmt.Errorf("Invalid rule '%s'", s)
+	rule := holidayRule{}
+
+	if strings.HasPrefix(s, "E") {
+		n, err := strconv.Atoi(s[1:])
+		if err != nil || (s[1] != '+' && s[1] != '-') {
+			return rule, invalid
+		}
+		rule.kind, rule.day = easterRule, n
+		return rule, nil
+	}
+
+	switch {
+	case strings.HasSuffix(s, "+"):
+		rule.observance, s = observeNextWorkingDay, strings.TrimSuffix(s, "+")
+	case strings.HasSuffix(s, "~"):
+		rule.observance, s = observeNearestWeekday, strings.TrimSuffix(s, "~")
+	}
+
+	if len(s) < 5 || s[2] != '-' {
+		return rule, invalid
+	}
+	month, err := strconv.Atoi(s[:2])
+	if err != nil || month < 1 || month > 12 {
+		return rule, invalid
+	}
+	rule.month = time.Month(month)
+	s = s[3:]
+
+	if day, err := strconv.Atoi(s); err == nil {
+		if day < 1 || day > 31 {
+			return rule, invalid
+		}
+		rule.kind, rule.day = fixedRule, day
+		return rule, nil
+	}
+	if rule.observance != observeOnDay {
+		return rule, invalid
+	}
+
+	weekday, ok := weekdays[s[:3]]
+	if !ok || len(s) < 5 {
+		return rule, invalid
+	}
+	rule.weekday = weekday
+	switch n := s[4:]; {
+	case s[3] == '-' && n == "L":
+		rule.kind = lastWeekdayRule
+	case s[3] == '-':
+		rule.kind = nthWeekdayRule
+		if rule.day, err = strconv.Atoi(n); err != nil || rule.day < 1 || rule.day > 5 {
+			return rule, invalid
+		}
+	case s[3] == '<':
+		rule.kind = weekdayBeforeRule
+		if rule.day, err = strconv.Atoi(n); err != nil || rule.day < 2 || rule.day > 31 {
+			return rule, invalid
+		}
+	default:
+		return rule, invalid
+	}
+	return rule, nil
+}
+
+// date returns the date of the holiday in a year (before any substitution)
+func (r holidayRule) date(year int) date {
+	switch r.kind {
+	case nthWeekdayRule:
+		first := date{year, r.month, 1}
+		offset := (int(r.weekday) - int(first.weekday()) + 7) % 7
+		return first.addDays(offset + 7*(r.day-1))
+	case lastWeekdayRule:
+		last := date{year, r.month + 1, 0}.normalised()
+		return last.addDays(-((int(last.weekday()) - int(r.weekday) + 7) % 7))
+	case weekdayBeforeRule:
+		before := date{year, r.month, r.day - 1}
+		return before.addDays(-((int(before.weekday()) - int(r.weekday) + 7) % 7))
+	case easterRule:
+		return easter(year).addDays(r.day)
+	}
+	return date{year, r.month, r.day}
+}
+
+// easter returns the date of Easter Sunday in the Gregorian calendar (the "Anonymous Gregorian algorithm")
+func easter(year int) date {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date{year, time.Month(month), day}
+}
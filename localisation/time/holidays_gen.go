@@ -0,0 +1,52 @@
+// +build ignore
+
+// holidays_gen.go embeds the holiday data files (holidays/<country>.txt) in holidays_data.go, so they're compiled
+// into the package. Run "go generate" after editing them.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	paths, err := filepath.Glob(filepath.Join("holidays", "*.txt"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(paths)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "// Code generated by holidays_gen.go from holidays/*.txt; DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package time")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "// holidayData holds the holiday rules of each country, keyed by ISO 3166-1 alpha-2 code")
+	fmt.Fprintln(buf, "var holidayData = map[string]string{")
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if bytes.ContainsRune(b, '`') {
+			log.Fatalf("%s contains a backquote", path)
+		}
+		country := strings.ToUpper(strings.TrimSuffix(filepath.Base(path), ".txt"))
+		fmt.Fprintf(buf, "%q: `\n%s`,\n", country, b)
+	}
+	fmt.Fprintln(buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("holidays_data.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
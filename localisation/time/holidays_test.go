@@ -0,0 +1,147 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/HailoOSS/go-hailo-lib/localisation/hob"
+)
+
+func TestHolidayData(t *testing.T) {
+	for country := range holidayData {
+		_, err := NewCalendar(country, time.UTC)
+		assert.NoError(t, err, "Invalid holiday data for %s", country)
+	}
+
+	_, err := NewCalendar("XX", time.UTC)
+	assert.Error(t, err)
+}
+
+func TestEaster(t *testing.T) {
+	testCases := map[int]date{
+		2000: {2000, time.April, 23},
+		2015: {2015, time.April, 5},
+		2016: {2016, time.March, 27},
+		2019: {2019, time.April, 21},
+		2038: {2038, time.April, 25},
+	}
+	for year, expected := range testCases {
+		assert.Equal(t, expected, easter(year), "Wrong Easter for %d", year)
+	}
+}
+
+func TestHolidayName(t *testing.T) {
+	testCases := []struct {
+		country  string
+		date     date
+		expected string
+	}{
+		{"GB", date{2015, time.January, 1}, "New Year's Day"},
+		{"GB", date{2015, time.April, 3}, "Good Friday"},
+		{"GB", date{2015, time.April, 6}, "Easter Monday"},
+		{"GB", date{2015, time.May, 4}, "Early May Bank Holiday"},
+		{"GB", date{2015, time.May, 25}, "Spring Bank Holiday"},
+		{"GB", date{2015, time.August, 31}, "Summer Bank Holiday"},
+		{"GB", date{2015, time.December, 26}, "Boxing Day"},
+		{"GB", date{2015, time.December, 28}, "Boxing Day (substitute day)"},
+		{"GB", date{2016, time.December, 26}, "Boxing Day"},
+		{"GB", date{2016, time.December, 27}, "Christmas Day (substitute day)"},
+		{"GB", date{2015, time.December, 29}, ""},
+		{"GB", date{2015, time.March, 17}, ""},
+		{"IE", date{2015, time.March, 17}, "St Patrick's Day"},
+		{"IE", date{2015, time.October, 26}, "October Bank Holiday"},
+		{"US", date{2015, time.November, 26}, "Thanksgiving Day"},
+		{"US", date{2015, time.July, 3}, "Independence Day (observed)"},
+		{"US", date{2021, time.December, 31}, "New Year's Day (observed)"},
+		{"US", date{2015, time.January, 19}, "Martin Luther King Jr. Day"},
+		{"CA", date{2015, time.May, 18}, "Victoria Day"},
+		{"CA", date{2016, time.May, 23}, "Victoria Day"},
+		{"FR", date{2015, time.May, 14}, "Ascension Day"},
+		{"DE", date{2015, time.May, 25}, "Whit Monday"},
+		{"ES", date{2015, time.October, 12}, "National Day"},
+		{"IT", date{2015, time.June, 2}, "Republic Day"},
+	}
+
+	for _, tc := range testCases {
+		cal, err := NewCalendar(tc.country, time.UTC)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tc.expected, cal.HolidayName(tc.date.in(time.UTC)), "%s on %v", tc.country, tc.date)
+		}
+	}
+}
+
+func TestHolidaysInLocation(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	us, _ := NewCalendar("US", newYork)
+	utc, _ := NewCalendar("US", time.UTC)
+
+	// 03:00 UTC on the 27th is still Thanksgiving evening in New York
+	ts := time.Date(2015, time.November, 27, 3, 0, 0, 0, time.UTC)
+	assert.True(t, us.IsHoliday(ts))
+	assert.False(t, utc.IsHoliday(ts))
+	assert.Equal(t, "Thanksgiving Day", us.HolidayName(ts))
+}
+
+func TestWorkingDays(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+	cal, _ := HobCalendar(&hob.Hob{
+		Code:     "LON",
+		Timezone: "Europe/London",
+		Country:  hob.Country{ISO_3166_1: "GB"},
+	})
+	assert.Equal(t, london.String(), cal.Location().String())
+
+	christmasEve := time.Date(2015, time.December, 24, 17, 0, 0, 0, london)
+	assert.True(t, cal.IsWorkingDay(christmasEve))
+	assert.Equal(t, time.Date(2015, time.December, 29, 0, 0, 0, 0, london), cal.NextWorkingDay(christmasEve))
+
+	friday := time.Date(2015, time.May, 22, 12, 0, 0, 0, london)
+	assert.Equal(t, time.Date(2015, time.May, 26, 0, 0, 0, 0, london), cal.NextWorkingDay(friday))
+	assert.False(t, cal.IsWorkingDay(time.Date(2015, time.May, 23, 12, 0, 0, 0, london)))
+
+	holidays := cal.Holidays(2015)
+	if assert.Len(t, holidays, 9) {
+		assert.Equal(t, "New Year's Day", holidays[0].Name)
+		assert.Equal(t, "Boxing Day (substitute day)", holidays[8].Name)
+	}
+
+	_, err := HobCalendar(&hob.Hob{Code: "LON", Country: hob.Country{ISO_3166_1: "GB"}})
+	assert.Error(t, err, "Expected an error without a timezone")
+}
+
+func TestCalendarsShared(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+	c1, err := NewCalendar("GB", london)
+	assert.NoError(t, err)
+	c2, err := NewCalendar("gb", london)
+	assert.NoError(t, err)
+	assert.True(t, c1 == c2, "Expected the calendar to be shared")
+
+	c3, err := NewCalendar("GB", time.UTC)
+	assert.NoError(t, err)
+	assert.False(t, c1 == c3, "Expected a calendar per location")
+}
+
+func TestParseHolidayRules(t *testing.T) {
+	rules, err := parseHolidayRules(`
+		# A comment
+		12-25+ Christmas Day
+		05-MON<25 Victoria Day
+		E-2 Good Friday
+	`)
+	if assert.NoError(t, err) && assert.Len(t, rules, 3) {
+		assert.Equal(t, holidayRule{name: "Christmas Day", kind: fixedRule, month: time.December, day: 25,
+			observance: observeNextWorkingDay}, rules[0])
+		assert.Equal(t, holidayRule{name: "Victoria Day", kind: weekdayBeforeRule, month: time.May, day: 25,
+			weekday: time.Monday}, rules[1])
+		assert.Equal(t, holidayRule{name: "Good Friday", kind: easterRule, day: -2}, rules[2])
+	}
+
+	for _, invalid := range []string{"12-25", "13-01 Foo", "12-32 Foo", "E Foo", "E2 Foo", "05-XXX-1 Foo",
+		"05-MON-6 Foo", "05-MON-1+ Foo", "05-MON>25 Foo", "5-1 Foo"} {
+		_, err := parseHolidayRules(invalid)
+		assert.Error(t, err, "Rule '%s' should be invalid", invalid)
+	}
+}
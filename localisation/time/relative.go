@@ -0,0 +1,315 @@
+package time
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HailoOSS/go-hailo-lib/i18n"
+)
+
+type unit int
+
+const (
+	second unit = iota
+	minute
+	hour
+	day
+	month
+	year
+)
+
+// plural holds the singular and plural forms of a unit, each a format taking the count, eg: {"%d hour", "%d hours"}
+type plural [2]string
+
+// phrases holds one language's words for relative times and durations
+type phrases struct {
+	now       string
+	future    string // Format taking the count and unit, eg: "in %s"
+	past      string // Format taking the count and unit, eg: "%s ago"
+	relative  [6]plural
+	duration  [6]plural // If different to relative (eg: German uses the dative case after "in" and "vor")
+	separator string    // Between the parts of a duration
+	singular  func(n int64) bool
+}
+
+func isOne(n int64) bool {
+	return n == 1
+}
+
+// norwegian is shared by Bokmål ("nb") and the generic "no"
+var norwegian = &phrases{
+	now:    "akkurat nå",
+	future: "om %s",
+	past:   "for %s siden",
+	relative: [6]plural{
+		{"%d sekund", "%d sekunder"},
+		{"%d minutt", "%d minutter"},
+		{"%d time", "%d timer"},
+		{"%d dag", "%d dager"},
+		{"%d måned", "%d måneder"},
+		{"%d år", "%d år"},
+	},
+	separator: " ",
+	singular:  isOne,
+}
+
+// languagePhrases holds the phrases of each language with a monday locale (so dates and relative times are formatted
+// in the same language), plus Swedish and Norwegian. Other languages fall back to English.
+var languagePhrases = map[string]*phrases{
+	"en": {
+		now:    "just now",
+		future: "in %s",
+		past:   "%s ago",
+		relative: [6]plural{
+			{"%d second", "%d seconds"},
+			{"%d minute", "%d minutes"},
+			{"%d hour", "%d hours"},
+			{"%d day", "%d days"},
+			{"%d month", "%d months"},
+			{"%d year", "%d years"},
+		},
+		separator: " ",
+		singular:  isOne,
+	},
+	"es": {
+		now:    "ahora mismo",
+		future: "dentro de %s",
+		past:   "hace %s",
+		relative: [6]plural{
+			{"%d segundo", "%d segundos"},
+			{"%d minuto", "%d minutos"},
+			{"%d hora", "%d horas"},
+			{"%d día", "%d días"},
+			{"%d mes", "%d meses"},
+			{"%d año", "%d años"},
+		},
+		separator: " ",
+		singular:  isOne,
+	},
+	"fr": {
+		now:    "à l'instant",
+		future: "dans %s",
+		past:   "il y a %s",
+		relative: [6]plural{
+			{"%d seconde", "%d secondes"},
+			{"%d minute", "%d minutes"},
+			{"%d heure", "%d heures"},
+			{"%d jour", "%d jours"},
+			{"%d mois", "%d mois"},
+			{"%d an", "%d ans"},
+		},
+		separator: " ",
+		singular: func(n int64) bool {
+			return n == 0 || n == 1
+		},
+	},
+	"de": {
+		now:    "gerade eben",
+		future: "in %s",
+		past:   "vor %s",
+		relative: [6]plural{
+			{"%d Sekunde", "%d Sekunden"},
+			{"%d Minute", "%d Minuten"},
+			{"%d Stunde", "%d Stunden"},
+			{"%d Tag", "%d Tagen"},
+			{"%d Monat", "%d Monaten"},
+			{"%d Jahr", "%d Jahren"},
+		},
+		duration: [6]plural{
+			{"%d Sekunde", "%d Sekunden"},
+			{"%d Minute", "%d Minuten"},
+			{"%d Stunde", "%d Stunden"},
+			{"%d Tag", "%d Tage"},
+			{"%d Monat", "%d Monate"},
+			{"%d Jahr", "%d Jahre"},
+		},
+		separator: " ",
+		singular:  isOne,
+	},
+	"it": {
+		now:    "proprio ora",
+		future: "tra %s",
+		past:   "%s fa",
+		relative: [6]plural{
+			{"%d secondo", "%d secondi"},
+			{"%d minuto", "%d minuti"},
+			{"%d ora", "%d ore"},
+			{"%d giorno", "%d giorni"},
+			{"%d mese", "%d mesi"},
+			{"%d anno", "%d anni"},
+		},
+		separator: " ",
+		singular:  isOne,
+	},
+	"pt": {
+		now:    "agora mesmo",
+		future: "em %s",
+		past:   "há %s",
+		relative: [6]plural{
+			{"%d segundo", "%d segundos"},
+			{"%d minuto", "%d minutos"},
+			{"%d hora", "%d horas"},
+			{"%d dia", "%d dias"},
+			{"%d mês", "%d meses"},
+			{"%d ano", "%d anos"},
+		},
+		separator: " ",
+		singular:  isOne,
+	},
+	"nl": {
+		now:    "zojuist",
+		future: "over %s",
+		past:   "%s geleden",
+		relative: [6]plural{
+			{"%d seconde", "%d seconden"},
+			{"%d minuut", "%d minuten"},
+			{"%d uur", "%d uur"},
+			{"%d dag", "%d dagen"},
+			{"%d maand", "%d maanden"},
+			{"%d jaar", "%d jaar"},
+		},
+		separator: " ",
+		singular:  isOne,
+	},
+	"da": {
+		now:    "lige nu",
+		future: "om %s",
+		past:   "for %s siden",
+		relative: [6]plural{
+			{"%d sekund", "%d sekunder"},
+			{"%d minut", "%d minutter"},
+			{"%d time", "%d timer"},
+			{"%d dag", "%d dage"},
+			{"%d måned", "%d måneder"},
+			{"%d år", "%d år"},
+		},
+		separator: " ",
+		singular:  isOne,
+	},
+	"sv": {
+		now:    "just nu",
+		future: "om %s",
+		past:   "för %s sedan",
+		relative: [6]plural{
+			{"%d sekund", "%d sekunder"},
+			{"%d minut", "%d minuter"},
+			{"%d timme", "%d timmar"},
+			{"%d dag", "%d dagar"},
+			{"%d månad", "%d månader"},
+			{"%d år", "%d år"},
+		},
+		separator: " ",
+		singular:  isOne,
+	},
+	"nb": norwegian,
+	"no": norwegian,
+	"ja": {
+		now:    "たった今",
+		future: "%s後",
+		past:   "%s前",
+		relative: [6]plural{
+			{"%d秒", "%d秒"},
+			{"%d分", "%d分"},
+			{"%d時間", "%d時間"},
+			{"%d日", "%d日"},
+			{"%dか月", "%dか月"},
+			{"%d年", "%d年"},
+		},
+		separator: "",
+		singular:  isOne,
+	},
+}
+
+// localePhrases returns the phrases for the locale's language, falling back to English
+func localePhrases(locale string) *phrases {
+	if p, ok := languagePhrases[i18n.Language(i18n.NormaliseLocale(locale))]; ok {
+		return p
+	}
+	return languagePhrases["en"]
+}
+
+func (p *phrases) format(forms plural, n int64) string {
+	if p.singular(n) {
+		return fmt.Sprintf(forms[0], n)
+	}
+	return fmt.Sprintf(forms[1], n)
+}
+
+func (p *phrases) durationForms(u unit) plural {
+	if p.duration[u][0] != "" {
+		return p.duration[u]
+	}
+	return p.relative[u]
+}
+
+// FormatRelative prints a localised string describing t relative to now, eg: "in 5 minutes" or "hace 2 horas". The
+// difference is rounded to the largest sensible unit.
+func FormatRelative(t, now time.Time, locale string) string {
+	p := localePhrases(locale)
+
+	d := t.Sub(now)
+	future := d > 0
+	if d < 0 {
+		d = -d
+	}
+
+	var u unit
+	var n int64
+	switch {
+	case d < 45*time.Second:
+		return p.now
+	case d < 45*time.Minute:
+		u, n = minute, roundDiv(d, time.Minute)
+	case d < 22*time.Hour:
+		u, n = hour, roundDiv(d, time.Hour)
+	case d < 26*24*time.Hour:
+		u, n = day, roundDiv(d, 24*time.Hour)
+	case d < 320*24*time.Hour:
+		u, n = month, roundDiv(d, 30*24*time.Hour)
+	default:
+		u, n = year, roundDiv(d, 365*24*time.Hour)
+	}
+
+	amount := p.format(p.relative[u], n)
+	if future {
+		return fmt.Sprintf(p.future, amount)
+	}
+	return fmt.Sprintf(p.past, amount)
+}
+
+// FormatDuration prints a localised, humanised duration (eg: for an ETA or waiting time), such as "1 hour 5 minutes"
+// or "45 segundos". Durations of a minute or more are rounded to the minute, and those of a day or more to the hour.
+// The duration is rounded before its units are chosen, so 59.6 seconds is "1 minute" rather than "60 seconds".
+func FormatDuration(d time.Duration, locale string) string {
+	p := localePhrases(locale)
+	if d < 0 {
+		d = -d
+	}
+
+	if seconds := roundDiv(d, time.Second); seconds < 60 {
+		return p.format(p.durationForms(second), seconds)
+	}
+
+	var units []unit
+	var counts []int64
+	if minutes := roundDiv(d, time.Minute); minutes < 24*60 {
+		units, counts = []unit{hour, minute}, []int64{minutes / 60, minutes % 60}
+	} else {
+		hours := roundDiv(d, time.Hour)
+		units, counts = []unit{day, hour}, []int64{hours / 24, hours % 24}
+	}
+
+	parts := make([]string, 0, len(units))
+	for i, u := range units {
+		if counts[i] > 0 {
+			parts = append(parts, p.format(p.durationForms(u), counts[i]))
+		}
+	}
+	return strings.Join(parts, p.separator)
+}
+
+// roundDiv divides d by unit, rounding to the nearest whole unit
+func roundDiv(d, unit time.Duration) int64 {
+	return int64((d + unit/2) / unit)
+}
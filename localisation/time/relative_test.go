@@ -0,0 +1,108 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HailoOSS/monday"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/HailoOSS/go-hailo-lib/i18n"
+)
+
+func TestFormatRelative(t *testing.T) {
+	now := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		offset   time.Duration
+		locale   string
+		expected string
+	}{
+		{10 * time.Second, "en_GB", "just now"},
+		{-30 * time.Second, "en_GB", "just now"},
+		{5 * time.Minute, "en_GB", "in 5 minutes"},
+		{-time.Minute, "en_GB", "1 minute ago"},
+		{-2 * time.Hour, "en_GB", "2 hours ago"},
+		{50 * time.Minute, "en_US", "in 1 hour"},
+		{3 * 24 * time.Hour, "en_GB", "in 3 days"},
+		{-60 * 24 * time.Hour, "en_GB", "2 months ago"},
+		{400 * 24 * time.Hour, "en_GB", "in 1 year"},
+		{5 * time.Minute, "es_ES", "dentro de 5 minutos"},
+		{-2 * time.Hour, "es-ES", "hace 2 horas"},
+		{-time.Hour, "es_ES", "hace 1 hora"},
+		{-2 * time.Hour, "fr_FR", "il y a 2 heures"},
+		{5 * time.Minute, "fr_FR", "dans 5 minutes"},
+		{-2 * 24 * time.Hour, "de_DE", "vor 2 Tagen"},
+		{5 * time.Minute, "it_IT", "tra 5 minuti"},
+		{-2 * time.Hour, "pt_BR", "há 2 horas"},
+		{-2 * time.Hour, "nl_NL", "2 uur geleden"},
+		{5 * time.Minute, "ja_JP", "5分後"},
+		{-2 * time.Hour, "da_DK", "for 2 timer siden"},
+		{5 * time.Minute, "sv_SE", "om 5 minuter"},
+		{-time.Hour, "nb_NO", "for 1 time siden"},
+		{5 * time.Minute, "xx_XX", "in 5 minutes"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, FormatRelative(now.Add(tc.offset), now, tc.locale), "%v in %s", tc.offset,
+			tc.locale)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	testCases := []struct {
+		d        time.Duration
+		locale   string
+		expected string
+	}{
+		{45 * time.Second, "en_GB", "45 seconds"},
+		{time.Second, "en_GB", "1 second"},
+		{0, "en_GB", "0 seconds"},
+		{time.Minute, "en_GB", "1 minute"},
+		{90 * time.Second, "en_GB", "2 minutes"},
+		{65 * time.Minute, "en_GB", "1 hour 5 minutes"},
+		{2 * time.Hour, "en_GB", "2 hours"},
+		{-5 * time.Minute, "en_GB", "5 minutes"},
+		{26 * time.Hour, "en_GB", "1 day 2 hours"},
+		{65 * time.Minute, "es_ES", "1 hora 5 minutos"},
+		{0, "fr_FR", "0 seconde"},
+		{3 * 24 * time.Hour, "de_DE", "3 Tage"},
+		{65 * time.Minute, "ja_JP", "1時間5分"},
+		{59*time.Second + 600*time.Millisecond, "en_GB", "1 minute"},
+		{59*time.Minute + 40*time.Second, "en_GB", "1 hour"},
+		{23*time.Hour + 59*time.Minute + 40*time.Second, "en_GB", "1 day"},
+		{23*time.Hour + 59*time.Minute + 20*time.Second, "en_GB", "23 hours 59 minutes"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, FormatDuration(tc.d, tc.locale), "%v in %s", tc.d, tc.locale)
+	}
+}
+
+func TestPhrasesCoverMondayLocales(t *testing.T) {
+	for _, l := range monday.ListLocales() {
+		_, ok := languagePhrases[i18n.Language(i18n.NormaliseLocale(string(l)))]
+		assert.True(t, ok, "No relative time phrases for monday locale %s", l)
+	}
+}
+
+func TestFormatStyle(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+	ts := time.Date(2015, 6, 1, 17, 4, 0, 0, time.UTC)
+
+	assert.Equal(t, "01/06/2015 18:04", FormatStyle(ts, "en_GB", london, StyleShort))
+	assert.Equal(t, "1 Jun 2015, 18:04", FormatStyle(ts, "en_GB", london, StyleMedium))
+	assert.Equal(t, "Monday 1 June 2015, 18:04", FormatStyle(ts, "en_GB", london, StyleLong))
+	assert.Equal(t, "06/01/2015 1:04 PM", FormatStyle(ts, "en_US", time.FixedZone("EDT", -4*3600), StyleShort))
+
+	assert.Equal(t, "02.01.2006 15:04", Layout("de_AT", StyleShort), "Should fall back to the language")
+	assert.Equal(t, "02/01/2006 15:04", Layout("xx", StyleShort), "Should fall back to en")
+	assert.Equal(t, Layout("en_GB", StyleMedium), Layout("en_GB", Style(42)))
+}
+
+func TestMondayLocale(t *testing.T) {
+	assert.Equal(t, "en_GB", string(mondayLocale("en-gb")))
+	assert.Equal(t, "fr_CA", string(mondayLocale("fr_CA")))
+	assert.Equal(t, "de_DE", string(mondayLocale("de_AT")))
+	assert.Equal(t, "en_GB", string(mondayLocale("xx_XX")))
+}
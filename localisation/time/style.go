@@ -0,0 +1,77 @@
+package time
+
+import (
+	"time"
+
+	"github.com/HailoOSS/monday"
+
+	"github.com/HailoOSS/go-hailo-lib/i18n"
+)
+
+// Style names a layout for formatting dates and times, which varies by locale
+type Style int
+
+const (
+	// StyleShort is numeric, eg: "02/01/2006 15:04"
+	StyleShort Style = iota
+	// StyleMedium abbreviates names, eg: "2 Jan 2006, 15:04"
+	StyleMedium
+	// StyleLong spells names out, eg: "Monday 2 January 2006, 15:04"
+	StyleLong
+)
+
+// styleLayouts holds the layouts for each style, keyed by locale or (as a fallback) language
+var styleLayouts = map[string][3]string{
+	"en":    {"02/01/2006 15:04", "2 Jan 2006, 15:04", "Monday 2 January 2006, 15:04"},
+	"en_US": {"01/02/2006 3:04 PM", "Jan 2, 2006, 3:04 PM", "Monday, January 2, 2006, 3:04 PM"},
+	"en_CA": {"2006-01-02 3:04 PM", "Jan 2, 2006, 3:04 PM", "Monday, January 2, 2006, 3:04 PM"},
+	"da":    {"02.01.2006 15.04", "2. Jan 2006 15.04", "Monday den 2. January 2006 15.04"},
+	"de":    {"02.01.2006 15:04", "2. Jan 2006, 15:04", "Monday, 2. January 2006, 15:04"},
+	"es":    {"02/01/2006 15:04", "2 Jan 2006, 15:04", "Monday, 2 de January de 2006, 15:04"},
+	"fr":    {"02/01/2006 15:04", "2 Jan 2006 15:04", "Monday 2 January 2006 15:04"},
+	"fr_CA": {"2006-01-02 15:04", "2 Jan 2006 15:04", "Monday 2 January 2006 15:04"},
+	"it":    {"02/01/2006 15:04", "2 Jan 2006, 15:04", "Monday 2 January 2006, 15:04"},
+	"ja":    {"2006/01/02 15:04", "2006/01/02 15:04", "2006年1月2日 Monday 15:04"},
+	"nl":    {"02-01-2006 15:04", "2 Jan 2006 15:04", "Monday 2 January 2006 15:04"},
+	"pt":    {"02/01/2006 15:04", "2 de Jan de 2006, 15:04", "Monday, 2 de January de 2006, 15:04"},
+}
+
+// Layout returns the layout for the style in the locale (eg: "en_GB"), falling back to the locale's language and then
+// to British English
+func Layout(locale string, style Style) string {
+	if style < StyleShort || style > StyleLong {
+		style = StyleMedium
+	}
+	locale = i18n.NormaliseLocale(locale)
+	if layouts, ok := styleLayouts[locale]; ok {
+		return layouts[style]
+	}
+	if layouts, ok := styleLayouts[i18n.Language(locale)]; ok {
+		return layouts[style]
+	}
+	return styleLayouts["en"][style]
+}
+
+// FormatStyle prints a localised string representing the time in the given style
+func FormatStyle(t time.Time, locale string, location *time.Location, style Style) string {
+	return monday.Format(t.In(location), Layout(locale, style), mondayLocale(locale))
+}
+
+// mondayLocale finds the monday.Locale for a locale, falling back to another of the same language and then to
+// British English
+func mondayLocale(locale string) monday.Locale {
+	locale = i18n.NormaliseLocale(locale)
+	var sameLanguage monday.Locale
+	for _, l := range monday.ListLocales() {
+		if string(l) == locale {
+			return l
+		}
+		if sameLanguage == "" && i18n.Language(string(l)) == i18n.Language(locale) {
+			sameLanguage = l
+		}
+	}
+	if sameLanguage != "" {
+		return sameLanguage
+	}
+	return monday.LocaleEnGB
+}
@@ -0,0 +1,168 @@
+package time
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/HailoOSS/go-hailo-lib/localisation/hob"
+)
+
+// WallClockKind describes how a local wall-clock time maps to real instants
+type WallClockKind int
+
+const (
+	// WallClockNormal times occur exactly once
+	WallClockNormal WallClockKind = iota
+	// WallClockSkipped times don't occur, as the clocks go forward over them (eg: 01:30 on the last Sunday of March
+	// in London)
+	WallClockSkipped
+	// WallClockRepeated times occur twice, as the clocks go back over them (eg: 01:30 on the last Sunday of October in
+	// London)
+	WallClockRepeated
+)
+
+func (k WallClockKind) String() string {
+	switch k {
+	case WallClockNormal:
+		return "normal"
+	case WallClockSkipped:
+		return "skipped"
+	case WallClockRepeated:
+		return "repeated"
+	}
+	return fmt.Sprintf("WallClockKind(%d)", int(k))
+}
+
+// Disambiguation determines which instant a skipped or repeated wall-clock time resolves to
+type Disambiguation int
+
+const (
+	// Compatible resolves repeated times to the earlier instant, and skipped times to the instant the same distance
+	// after the transition (eg: 01:30 becomes 02:30 in London), as most calendaring software does
+	Compatible Disambiguation = iota
+	// Earlier resolves repeated times to the earlier instant, and skipped times to the instant the same distance
+	// before the transition (eg: 01:30 becomes 00:30 in London)
+	Earlier
+	// Later resolves repeated times to the later instant, and skipped times as Compatible does
+	Later
+	// Reject returns an error for skipped or repeated times
+	Reject
+)
+
+// WallClockError is returned when a wall-clock time can't be resolved with the Reject policy
+type WallClockError struct {
+	Kind     WallClockKind
+	WallTime string
+	Location string
+}
+
+func (e *WallClockError) Error() string {
+	return fmt.Sprintf("Local time %s is %s in %s", e.WallTime, e.Kind, e.Location)
+}
+
+// WallClock returns how the wall-clock time maps to instants in the location, and those instants (none if skipped,
+// one if normal, the earlier first if repeated)
+func WallClock(year int, month time.Month, day, hour, min, sec int, loc *time.Location) (WallClockKind, []time.Time) {
+	// The wall-clock time as if it were UTC, from which each possible offset is subtracted
+	naive := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	wall := naive.Format("2006-01-02 15:04:05")
+
+	instants := make([]time.Time, 0, 2)
+	for _, offset := range offsetsAround(naive, loc) {
+		instant := naive.Add(-time.Duration(offset) * time.Second).In(loc)
+		if instant.Format("2006-01-02 15:04:05") == wall {
+			instants = append(instants, instant)
+		}
+	}
+	sort.Sort(timesAscending(instants))
+
+	switch len(instants) {
+	case 0:
+		return WallClockSkipped, instants
+	case 1:
+		return WallClockNormal, instants
+	}
+	return WallClockRepeated, instants
+}
+
+// LocalTime returns the instant the wall-clock time occurs in the location, resolving skipped and repeated times with
+// policy. Unlike time.Date, which of the two instants a repeated time resolves to is always defined.
+func LocalTime(year int, month time.Month, day, hour, min, sec int, loc *time.Location,
+	policy Disambiguation) (time.Time, error) {
+
+	kind, instants := WallClock(year, month, day, hour, min, sec, loc)
+	if kind != WallClockNormal && policy == Reject {
+		return time.Time{}, &WallClockError{
+			Kind:     kind,
+			WallTime: time.Date(year, month, day, hour, min, sec, 0, time.UTC).Format("2006-01-02 15:04:05"),
+			Location: loc.String(),
+		}
+	}
+
+	switch kind {
+	case WallClockNormal:
+		return instants[0], nil
+	case WallClockRepeated:
+		if policy == Later {
+			return instants[1], nil
+		}
+		return instants[0], nil
+	}
+
+	// Skipped: interpret the wall-clock time with the offset from before the transition (landing after the gap), or
+	// after it (landing before the gap)
+	naive := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	offsets := offsetsAround(naive, loc)
+	offset := offsets[0]
+	if policy == Earlier {
+		offset = offsets[len(offsets)-1]
+	}
+	return naive.Add(-time.Duration(offset) * time.Second).In(loc), nil
+}
+
+// LocalTimeOnDay returns the instant of a wall-clock time on the day days after the day t falls on in the location,
+// eg: LocalTimeOnDay(time.Now(), 1, 7, 30, loc, Compatible) for "7:30 tomorrow". Days are counted on the calendar, so
+// are unaffected by them being 23 or 25 hours long.
+func LocalTimeOnDay(t time.Time, days, hour, min int, loc *time.Location, policy Disambiguation) (time.Time, error) {
+	d := dateOf(t.In(loc)).addDays(days)
+	return LocalTime(d.year, d.month, d.day, hour, min, 0, loc, policy)
+}
+
+// AddLocalDays adds calendar days to t, keeping its wall-clock time in the location (which adding multiples of 24
+// hours does not across a DST transition)
+func AddLocalDays(t time.Time, days int, loc *time.Location, policy Disambiguation) (time.Time, error) {
+	local := t.In(loc)
+	d := dateOf(local).addDays(days)
+	return LocalTime(d.year, d.month, d.day, local.Hour(), local.Minute(), local.Second(), loc, policy)
+}
+
+// HobLocalTime returns the instant the wall-clock time occurs in the HOB's timezone, as LocalTime
+func HobLocalTime(h *hob.Hob, year int, month time.Month, day, hour, min int,
+	policy Disambiguation) (time.Time, error) {
+
+	loc, err := h.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return LocalTime(year, month, day, hour, min, 0, loc, policy)
+}
+
+// offsetsAround returns the distinct UTC offsets (in seconds) in use in the location in the day either side of the
+// naive time (which covers any real offset), in the order they were in use
+func offsetsAround(naive time.Time, loc *time.Location) []int {
+	offsets := make([]int, 0, 2)
+	for h := -24; h <= 24; h += 3 {
+		_, offset := naive.Add(time.Duration(h) * time.Hour).In(loc).Zone()
+		if len(offsets) == 0 || offsets[len(offsets)-1] != offset {
+			offsets = append(offsets, offset)
+		}
+	}
+	return offsets
+}
+
+type timesAscending []time.Time
+
+func (t timesAscending) Len() int           { return len(t) }
+func (t timesAscending) Less(i, j int) bool { return t[i].Before(t[j]) }
+func (t timesAscending) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
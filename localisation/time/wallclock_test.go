@@ -0,0 +1,116 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/HailoOSS/go-hailo-lib/localisation/hob"
+)
+
+func TestWallClockLondon(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+
+	// Clocks went forward at 01:00 GMT on 29th March 2015, and back at 01:00 GMT on 25th October 2015
+	testCases := []struct {
+		month    time.Month
+		day      int
+		hour     int
+		min      int
+		policy   Disambiguation
+		kind     WallClockKind
+		expected string // In UTC
+	}{
+		{time.March, 29, 0, 30, Compatible, WallClockNormal, "2015-03-29 00:30"},
+		{time.March, 29, 1, 0, Compatible, WallClockSkipped, "2015-03-29 01:00"},
+		{time.March, 29, 1, 30, Compatible, WallClockSkipped, "2015-03-29 01:30"},
+		{time.March, 29, 1, 30, Later, WallClockSkipped, "2015-03-29 01:30"},
+		{time.March, 29, 1, 30, Earlier, WallClockSkipped, "2015-03-29 00:30"},
+		{time.March, 29, 2, 0, Compatible, WallClockNormal, "2015-03-29 01:00"},
+		{time.October, 25, 0, 59, Compatible, WallClockNormal, "2015-10-24 23:59"},
+		{time.October, 25, 1, 30, Compatible, WallClockRepeated, "2015-10-25 00:30"},
+		{time.October, 25, 1, 30, Earlier, WallClockRepeated, "2015-10-25 00:30"},
+		{time.October, 25, 1, 30, Later, WallClockRepeated, "2015-10-25 01:30"},
+		{time.October, 25, 2, 0, Compatible, WallClockNormal, "2015-10-25 02:00"},
+	}
+
+	for _, tc := range testCases {
+		kind, _ := WallClock(2015, tc.month, tc.day, tc.hour, tc.min, 0, london)
+		assert.Equal(t, tc.kind, kind, "%d %s %02d:%02d", tc.day, tc.month, tc.hour, tc.min)
+
+		actual, err := LocalTime(2015, tc.month, tc.day, tc.hour, tc.min, 0, london, tc.policy)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tc.expected, actual.UTC().Format("2006-01-02 15:04"), "%d %s %02d:%02d (policy %d)",
+				tc.day, tc.month, tc.hour, tc.min, tc.policy)
+			assert.Equal(t, london, actual.Location())
+		}
+
+		_, err = LocalTime(2015, tc.month, tc.day, tc.hour, tc.min, 0, london, Reject)
+		if tc.kind == WallClockNormal {
+			assert.NoError(t, err)
+		} else if assert.Error(t, err) {
+			assert.Equal(t, tc.kind, err.(*WallClockError).Kind)
+		}
+	}
+}
+
+func TestWallClockNewYork(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	// Clocks went forward at 02:00 EST on 8th March 2015, and back at 02:00 EDT on 1st November 2015
+	kind, instants := WallClock(2015, time.March, 8, 2, 30, 0, newYork)
+	assert.Equal(t, WallClockSkipped, kind)
+	assert.Empty(t, instants)
+
+	actual, _ := LocalTime(2015, time.March, 8, 2, 30, 0, newYork, Compatible)
+	assert.Equal(t, "2015-03-08 03:30 EDT", actual.Format("2006-01-02 15:04 MST"))
+	actual, _ = LocalTime(2015, time.March, 8, 2, 30, 0, newYork, Earlier)
+	assert.Equal(t, "2015-03-08 01:30 EST", actual.Format("2006-01-02 15:04 MST"))
+
+	kind, instants = WallClock(2015, time.November, 1, 1, 30, 0, newYork)
+	assert.Equal(t, WallClockRepeated, kind)
+	if assert.Len(t, instants, 2) {
+		assert.Equal(t, "01:30 EDT", instants[0].Format("15:04 MST"))
+		assert.Equal(t, "01:30 EST", instants[1].Format("15:04 MST"))
+	}
+	actual, _ = LocalTime(2015, time.November, 1, 1, 30, 0, newYork, Later)
+	assert.Equal(t, "2015-11-01 06:30", actual.UTC().Format("2006-01-02 15:04"))
+
+	_, err := LocalTime(2015, time.November, 1, 1, 30, 0, newYork, Reject)
+	assert.EqualError(t, err, "Local time 2015-11-01 01:30:00 is repeated in America/New_York")
+}
+
+func TestLocalDays(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+
+	// 7:30 "tomorrow" from the evening before the clocks go forward is only 10.5 hours away
+	evening := time.Date(2015, time.March, 28, 20, 0, 0, 0, london)
+	tomorrow, err := LocalTimeOnDay(evening, 1, 7, 30, london, Compatible)
+	assert.NoError(t, err)
+	assert.Equal(t, "2015-03-29 07:30 BST", tomorrow.Format("2006-01-02 15:04 MST"))
+	assert.Equal(t, 10*time.Hour+30*time.Minute, tomorrow.Sub(evening))
+
+	// Whereas adding 24 hours would change the wall-clock time
+	next, err := AddLocalDays(evening, 1, london, Compatible)
+	assert.NoError(t, err)
+	assert.Equal(t, "2015-03-29 20:00 BST", next.Format("2006-01-02 15:04 MST"))
+	assert.Equal(t, 23*time.Hour, next.Sub(evening))
+
+	next, err = AddLocalDays(time.Date(2015, time.October, 24, 1, 30, 0, 0, london), 1, london, Later)
+	assert.NoError(t, err)
+	assert.Equal(t, "2015-10-25 01:30 GMT", next.Format("2006-01-02 15:04 MST"))
+
+	_, err = LocalTimeOnDay(evening, 1, 1, 30, london, Reject)
+	assert.Error(t, err)
+}
+
+func TestHobLocalTime(t *testing.T) {
+	h := &hob.Hob{Code: "NYC", Timezone: "America/New_York"}
+	actual, err := HobLocalTime(h, 2015, time.July, 4, 9, 0, Compatible)
+	assert.NoError(t, err)
+	assert.Equal(t, "2015-07-04 13:00", actual.UTC().Format("2006-01-02 15:04"))
+
+	_, err = HobLocalTime(&hob.Hob{Code: "NYC"}, 2015, time.July, 4, 9, 0, Compatible)
+	assert.Error(t, err)
+}
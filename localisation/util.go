@@ -8,13 +8,18 @@ import (
 
 var hobRe = regexp.MustCompile(`^([A-Z]{3})`)
 
-// ExtractHobFromID takes an ID of the form LON1234 and returns LON
+// ExtractHobFromID takes an ID of the form LON1234 and returns LON. Unlike ParseID, the rest of the ID isn't checked.
 func ExtractHobFromID(id string) string {
+	hob, _ := splitID(id)
+	return hob
+}
+
+// splitID splits an ID into its HOB and the rest, returning no HOB if it doesn't start with one
+func splitID(id string) (string, string) {
 	if match := hobRe.FindStringSubmatch(id); match != nil {
-		return match[0]
-	} else {
-		return ""
+		return match[1], id[len(match[0]):]
 	}
+	return "", id
 }
 
 // ExtractCityFromID is backwards compatible version of ExtractHobFromID
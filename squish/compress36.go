@@ -8,7 +8,7 @@ import (
 // shorten the length of an id - so passing offset == 3 will compress leaving
 // the Hob part of the id untouched
 // This is needed for the loyalty service which must send id.s with relaively
-// short max. length
+// short max. length.
 func CompressTail36(offset int, in string) string {
 	return in[:offset] + Compress36(in[offset:])
 }
@@ -29,6 +29,16 @@ func Uncompress36(in string) string {
 	return switchBase(36, 10, in)
 }
 
+// Format36 formats a number in base-36, as Compress36 does
+func Format36(n uint64) string {
+	return strconv.FormatUint(n, 36)
+}
+
+// Parse36 parses a base-36 number, as Uncompress36 does, but returns an error if it's invalid rather than the input
+func Parse36(in string) (uint64, error) {
+	return strconv.ParseUint(in, 36, 64)
+}
+
 func switchBase(bi, bo int, in string) string {
 	ini, err := strconv.ParseUint(in, bi, 64)
 	if err != nil {
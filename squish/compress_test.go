@@ -55,3 +55,14 @@ func Test3(t *testing.T) {
 		t.Errorf("Input string: %s cycled string: %s", in, out)
 	}
 }
+
+func TestParse36(t *testing.T) {
+	n, err := Parse36("2j91s35n")
+	if err != nil || n != 198634378235 || Format36(n) != "2j91s35n" {
+		t.Errorf("Parsed: %d formatted: %s err: %v", n, Format36(n), err)
+	}
+
+	if _, err := Parse36("2j-91"); err == nil {
+		t.Errorf("Expected an error parsing an invalid number")
+	}
+}